@@ -1,6 +1,12 @@
 // Package certificateprofiles provides utilities to interact with the Horizon api.certificate.profile APIs.
 package certificateprofiles
 
+import (
+	"encoding/json"
+
+	"github.com/evertrust/horizon-go"
+)
+
 type CertificateProfileCryptoPolicy struct {
 	Centralized   bool `json:"centralized"`
 	Decentralized bool `json:"decentralized"`
@@ -62,7 +68,7 @@ type Profile struct {
 			Mandatory           bool `json:"mandatory"`
 		} `json:"teamPolicy"`
 	} `json:"certificateTemplate"`
-	Constraints  struct{} `json:"constraints"`
+	Constraints  json.RawMessage `json:"constraints,omitempty"`
 	CryptoPolicy struct {
 		AuthorizedKeyTypes       []string `json:"authorizedKeyTypes"`
 		Centralized              bool     `json:"centralized"`
@@ -74,15 +80,17 @@ type Profile struct {
 		ShowP12OnRecover         bool     `json:"showP12OnRecover"`
 		ShowP12PasswordOnRecover bool     `json:"showP12PasswordOnRecover"`
 	} `json:"cryptoPolicy"`
-	CsrDataMapping       struct{}                 `json:"csrDataMapping"`
+	CsrDataMapping       json.RawMessage          `json:"csrDataMapping,omitempty"`
 	Description          []map[string]interface{} `json:"description"`
 	DisplayName          []map[string]interface{} `json:"displayName"`
 	DnWhitelist          bool                     `json:"dnWhitelist"`
 	Enabled              bool                     `json:"enabled"`
 	EnrollAuthorizedCAs  []string                 `json:"enrollAuthorizedCas"`
+	GlobalHolderIdCount  int                      `json:"globalHolderIdCount,omitempty"`
 	Module               string                   `json:"module"`
 	Name                 string                   `json:"name"`
 	PkiConnector         string                   `json:"pkiConnector"`
+	ProfileHolderIdCount int                      `json:"profileHolderIdCount,omitempty"`
 	RenewalAuthorizedCAs []string                 `json:"renewalAuthorizedCas"`
 	RenewalPeriod        string                   `json:"renewalPeriod"`
 	RequestsPolicy       struct {
@@ -99,5 +107,26 @@ type Profile struct {
 		SelfRevoke    bool `json:"selfRevoke"`
 		SelfUpdate    bool `json:"selfUpdate"`
 	} `json:"selfPermissions"`
-	Triggers struct{} `json:"triggers"`
+	Triggers      json.RawMessage `json:"triggers,omitempty"`
+	unknownFields horizon.UnknownFields
+}
+
+func (p *Profile) UnmarshalJSON(data []byte) error {
+	type profile Profile
+	if err := json.Unmarshal(data, (*profile)(p)); err != nil {
+		return err
+	}
+	unknown, err := horizon.ExtractUnknownFields(data, profile{})
+	p.unknownFields = unknown
+	return err
+}
+
+func (p Profile) MarshalJSON() ([]byte, error) {
+	type profile Profile
+	return horizon.MarshalWithUnknownFields(profile(p), p.unknownFields)
+}
+
+// UnknownFields returns the JSON members sent by Horizon that this SDK version does not know about
+func (p *Profile) UnknownFields() horizon.UnknownFields {
+	return p.unknownFields
 }
@@ -1,3 +1,7 @@
+//go:build integration
+
+// Integration tests run against the Horizon instance given by the ENDPOINT, APIID and APIKEY environment variables
+
 package certificateprofiles
 
 import (
@@ -0,0 +1,38 @@
+package certificateprofiles
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProfileRoundTrip(t *testing.T) {
+	input := `{"name":"SSL","module":"webra","constraints":{"validity":"P1Y","keyTypes":["rsa-2048"]},"csrDataMapping":[{"from":"cn","to":"owner"}],"triggers":{"enroll":["notify"]},"futureField":{"nested":true}}`
+	var profile Profile
+	if err := json.Unmarshal([]byte(input), &profile); err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(profile.Constraints) != `{"validity":"P1Y","keyTypes":["rsa-2048"]}` || string(profile.CsrDataMapping) != `[{"from":"cn","to":"owner"}]` || string(profile.Triggers) != `{"enroll":["notify"]}` {
+		t.Errorf("raw members were not kept as sent: %s %s %s", profile.Constraints, profile.CsrDataMapping, profile.Triggers)
+	}
+	if len(profile.UnknownFields()) != 1 || string(profile.UnknownFields()["futureField"]) != `{"nested":true}` {
+		t.Errorf("unexpected unknown fields %v", profile.UnknownFields())
+	}
+	output, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var decoded map[string]json.RawMessage
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+	for name, expected := range map[string]string{
+		"constraints":    `{"validity":"P1Y","keyTypes":["rsa-2048"]}`,
+		"csrDataMapping": `[{"from":"cn","to":"owner"}]`,
+		"triggers":       `{"enroll":["notify"]}`,
+		"futureField":    `{"nested":true}`,
+	} {
+		if string(decoded[name]) != expected {
+			t.Errorf("%s was not re-emitted as sent: %s", name, decoded[name])
+		}
+	}
+}
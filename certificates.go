@@ -1,8 +1,11 @@
 package horizon
 
 import (
+	"crypto/x509"
 	"fmt"
+	"math"
 	"strings"
+	"time"
 )
 
 // Certificates
@@ -107,38 +110,151 @@ type TriggerResult struct {
 }
 
 type Certificate struct {
-	Id                    string           `json:"_id,omitempty"`
-	Module                string           `json:"module"`
-	Profile               string           `json:"profile,omitempty"`
-	Owner                 string           `json:"owner,omitempty"`
-	Team                  string           `json:"team,omitempty"`
-	ContactEmail          string           `json:"contactEmail,omitempty"`
-	Certificate           string           `json:"certificate"`
-	Thumbprint            string           `json:"thumbprint"`
-	SelfSigned            bool             `json:"selfSigned"`
-	PublicKeyThumbprint   string           `json:"publicKeyThumbprint"`
-	Dn                    string           `json:"dn"`
-	Serial                string           `json:"serial"`
-	Issuer                string           `json:"issuer"`
-	NotBefore             int              `json:"notBefore"`
-	NotAfter              int              `json:"notAfter"`
-	RevocationDate        int              `json:"revocationDate,omitempty"`
-	RevocationReason      RevocationReason `json:"revocationReason,omitempty"`
-	KeyType               string           `json:"keyType"`
-	SigningAlgorithm      string           `json:"signingAlgorithm"`
-	Revoked               bool             `json:"revoked"`
-	ThirdPartyData        []ThirdPartyItem `json:"thirdPartyData,omitempty"`
-	TriggerResults        []TriggerResult  `json:"triggerResults,omitempty"`
-	DiscoveryData         []DiscoveryData  `json:"discoveryData,omitempty"`
-	DiscoveryInfo         []DiscoveryInfo  `json:"discoveryInfo,omitempty"`
-	DiscoveryTrusted      *bool            `json:"discoveryTrusted,omitempty"`
-	Labels                []Label          `json:"labels,omitempty"`
-	SubjectAlternateNames []struct {
-		SanType string `json:"sanType"`
-		Value   string `json:"value"`
-	} `json:"subjectAlternateNames"`
-	Metadata []Metadata `json:"metadata"`
-	HolderId string     `json:"holderId"`
+	Id                    string                `json:"_id,omitempty"`
+	Module                string                `json:"module"`
+	Profile               string                `json:"profile,omitempty"`
+	Owner                 string                `json:"owner,omitempty"`
+	Team                  string                `json:"team,omitempty"`
+	ContactEmail          string                `json:"contactEmail,omitempty"`
+	Certificate           string                `json:"certificate"`
+	Thumbprint            string                `json:"thumbprint"`
+	SelfSigned            bool                  `json:"selfSigned"`
+	PublicKeyThumbprint   string                `json:"publicKeyThumbprint"`
+	Dn                    string                `json:"dn"`
+	Serial                string                `json:"serial"`
+	Issuer                string                `json:"issuer"`
+	NotBefore             int                   `json:"notBefore"`
+	NotAfter              int                   `json:"notAfter"`
+	RevocationDate        int                   `json:"revocationDate,omitempty"`
+	RevocationReason      RevocationReason      `json:"revocationReason,omitempty"`
+	KeyType               string                `json:"keyType"`
+	SigningAlgorithm      string                `json:"signingAlgorithm"`
+	Revoked               bool                  `json:"revoked"`
+	ThirdPartyData        []ThirdPartyItem      `json:"thirdPartyData,omitempty"`
+	TriggerResults        []TriggerResult       `json:"triggerResults,omitempty"`
+	DiscoveryData         []DiscoveryData       `json:"discoveryData,omitempty"`
+	DiscoveryInfo         []DiscoveryInfo       `json:"discoveryInfo,omitempty"`
+	DiscoveryTrusted      *bool                 `json:"discoveryTrusted,omitempty"`
+	Labels                []Label               `json:"labels,omitempty"`
+	SubjectAlternateNames SubjectAlternateNames `json:"subjectAlternateNames"`
+	Metadata              []Metadata            `json:"metadata"`
+	HolderId              string                `json:"holderId"`
+	preservedFields
+}
+
+// OutOfSyncThirdParties returns the third party items that do not hold the current certificate, i.e. whose fingerprint differs from the thumbprint.
+// Items removed from their third party are not expected to hold it and are ignored.
+func (c *Certificate) OutOfSyncThirdParties() []ThirdPartyItem {
+	return outOfSyncThirdParties(c.ThirdPartyData, c.Thumbprint)
+}
+
+func outOfSyncThirdParties(items []ThirdPartyItem, thumbprint string) []ThirdPartyItem {
+	var outOfSync []ThirdPartyItem
+	for _, item := range items {
+		if item.RemoveDate == 0 && !strings.EqualFold(item.Fingerprint, thumbprint) {
+			outOfSync = append(outOfSync, item)
+		}
+	}
+	return outOfSync
+}
+
+// FailedTriggers returns the trigger results in failure
+func (c *Certificate) FailedTriggers() []TriggerResult {
+	return failedTriggers(c.TriggerResults)
+}
+
+func failedTriggers(results []TriggerResult) []TriggerResult {
+	var failed []TriggerResult
+	for _, result := range results {
+		if result.Status == TriggerResultStatusFailure {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
+
+type SANType string
+
+const (
+	SANRfc822Name   SANType = "RFC822NAME"
+	SANDnsName      SANType = "DNSNAME"
+	SANUri          SANType = "URI"
+	SANIpAddress    SANType = "IPADDRESS"
+	SANOtherNameUpn SANType = "OTHERNAME_UPN"
+)
+
+type SubjectAlternateName struct {
+	SanType SANType `json:"sanType"`
+	Value   string  `json:"value"`
+}
+
+type SubjectAlternateNames []SubjectAlternateName
+
+// OfType returns the values of the SANs of the given type
+func (s SubjectAlternateNames) OfType(sanType SANType) []string {
+	var values []string
+	for _, san := range s {
+		if strings.EqualFold(string(san.SanType), string(sanType)) {
+			values = append(values, san.Value)
+		}
+	}
+	return values
+}
+
+// GetMetadata returns the value of a metadata, e.g. MetadataRenewedCertificateId
+func (c *Certificate) GetMetadata(metadata MetadataType) (string, bool) {
+	for _, m := range c.Metadata {
+		if m.Key == string(metadata) {
+			return m.Value, true
+		}
+	}
+	return "", false
+}
+
+// X509 parses the Certificate PEM. It is parsed on each call, callers needing it repeatedly should keep the result.
+func (c *Certificate) X509() (*x509.Certificate, error) {
+	return ParseCertificatePem(c.Certificate)
+}
+
+func (c *Certificate) GetNotBefore() time.Time {
+	return millisToTime(int64(c.NotBefore))
+}
+
+func (c *Certificate) GetNotAfter() time.Time {
+	return millisToTime(int64(c.NotAfter))
+}
+
+// GetRevocationDate returns the zero time if the certificate is not revoked
+func (c *Certificate) GetRevocationDate() time.Time {
+	return millisToTime(int64(c.RevocationDate))
+}
+
+func (c *Certificate) IsExpired() bool {
+	return c.isExpiredAt(time.Now())
+}
+
+// isExpiredAt returns false if the expiration date is unknown, e.g. when it was not requested in a search
+func (c *Certificate) isExpiredAt(now time.Time) bool {
+	return c.NotAfter != 0 && now.After(c.GetNotAfter())
+}
+
+// ExpiresWithin returns true if the certificate expires in less than d, or is already expired
+func (c *Certificate) ExpiresWithin(d time.Duration) bool {
+	return c.isExpiredAt(time.Now().Add(d))
+}
+
+// RemainingLifetimeRatio returns the remaining fraction of the validity period, from 1 when issued to 0 when expired
+func (c *Certificate) RemainingLifetimeRatio() float64 {
+	return c.remainingLifetimeRatioAt(time.Now())
+}
+
+func (c *Certificate) remainingLifetimeRatioAt(now time.Time) float64 {
+	lifetime := c.GetNotAfter().Sub(c.GetNotBefore())
+	if lifetime <= 0 {
+		return 0
+	}
+	ratio := float64(c.GetNotAfter().Sub(now)) / float64(lifetime)
+	return math.Max(0, math.Min(1, ratio))
 }
 
 type CertificateResponse struct {
@@ -155,12 +271,28 @@ type CertificateSearchQuery struct {
 }
 
 type CertificateSearchResult struct {
-	Id          string `json:"_id,omitempty"`
-	Module      string `json:"module"`
-	Dn          string `json:"dn"`
-	Serial      string `json:"serial"`
-	NotAfter    int    `json:"notAfter"`
-	Permissions struct {
+	Id         string `json:"_id,omitempty"`
+	Module     string `json:"module"`
+	Dn         string `json:"dn"`
+	Serial     string `json:"serial"`
+	Profile    string `json:"profile,omitempty"`
+	Owner      string `json:"owner,omitempty"`
+	Team       string `json:"team,omitempty"`
+	HolderId   string `json:"holderId,omitempty"`
+	NotBefore  int    `json:"notBefore,omitempty"`
+	NotAfter   int    `json:"notAfter"`
+	Thumbprint string `json:"thumbprint,omitempty"`
+	Issuer     string `json:"issuer,omitempty"`
+	// Revocation and SAN fields are only returned when requested in the search fields
+	Revoked               bool                  `json:"revoked,omitempty"`
+	RevocationDate        int                   `json:"revocationDate,omitempty"`
+	RevocationReason      RevocationReason      `json:"revocationReason,omitempty"`
+	SubjectAlternateNames SubjectAlternateNames `json:"subjectAlternateNames,omitempty"`
+	LastModificationDate  int64                 `json:"lastModificationDate,omitempty"`
+	// TriggerResults and ThirdPartyData are only returned when requested in the search fields
+	TriggerResults []TriggerResult  `json:"triggerResults,omitempty"`
+	ThirdPartyData []ThirdPartyItem `json:"thirdPartyData,omitempty"`
+	Permissions    struct {
 		Revoke         bool `json:"revoke"`
 		RequestRevoke  bool `json:"requestRevoke"`
 		Update         bool `json:"update"`
@@ -171,3 +303,13 @@ type CertificateSearchResult struct {
 		RequestMigrate bool `json:"requestMigrate"`
 	} `json:"permissions"`
 }
+
+// OutOfSyncThirdParties returns the third party items that do not hold the certificate, removed items excepted. Thumbprint and thirdPartyData must be requested in the search fields
+func (r *CertificateSearchResult) OutOfSyncThirdParties() []ThirdPartyItem {
+	return outOfSyncThirdParties(r.ThirdPartyData, r.Thumbprint)
+}
+
+// FailedTriggers returns the trigger results in failure, triggerResults must be requested in the search fields
+func (r *CertificateSearchResult) FailedTriggers() []TriggerResult {
+	return failedTriggers(r.TriggerResults)
+}
@@ -0,0 +1,221 @@
+package certificates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/http"
+)
+
+// Aggregations
+
+type AggregationQuery struct {
+	// Query is an optional HPQL filter restricting the certificates aggregated
+	Query string
+	// Fields to count the certificates by, e.g. "ca", "keyType", "signingAlgorithm" or "profile"
+	Fields []string
+	// ExpiryBuckets are the upper bounds of the expiry buckets, counted from now, e.g. 30 and 90 days
+	ExpiryBuckets []time.Duration
+	// Local forces the aggregation to be computed from a scan of the certificates instead of by Horizon
+	Local bool
+}
+
+type Bucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// ExpiryBucket counts the certificates expiring before Until and after the previous bucket.
+// The first bucket holds the expired certificates, the last one those expiring after every bound and has a zero Until.
+type ExpiryBucket struct {
+	Label string    `json:"label"`
+	Until time.Time `json:"until,omitempty"`
+	Count int       `json:"count"`
+}
+
+type Aggregation struct {
+	Total int `json:"total"`
+	// Facets holds the buckets of each field, from the most to the least common value
+	Facets map[string][]Bucket `json:"facets"`
+	Expiry []ExpiryBucket      `json:"expiry,omitempty"`
+	// Local is true if the aggregation was computed from a scan of the certificates
+	Local bool `json:"local"`
+}
+
+type aggregateRequest struct {
+	Query string `json:"query,omitempty"`
+	Field string `json:"field"`
+}
+
+// Aggregate counts the certificates matching the query by field and expiry.
+// If Horizon does not support aggregations, they are computed locally from a paged scan of the certificates.
+func (c *Client) Aggregate(query AggregationQuery) (*Aggregation, error) {
+	if !query.Local {
+		aggregation, err := c.aggregateRemote(query)
+		if err == nil || !isUnsupported(err) {
+			return aggregation, err
+		}
+	}
+	return c.aggregateLocal(query, time.Now())
+}
+
+// isUnsupported returns true if the error means that the endpoint does not exist on the Horizon instance
+func isUnsupported(err error) bool {
+	var horizonError *http.HorizonErrorResponse
+	if !errors.As(err, &horizonError) {
+		return false
+	}
+	return horizonError.Status == 404 || horizonError.Status == 405 || horizonError.Status == 501
+}
+
+func (c *Client) aggregateRemote(query AggregationQuery) (*Aggregation, error) {
+	aggregation := Aggregation{Facets: make(map[string][]Bucket)}
+	for _, field := range query.Fields {
+		jsonData, _ := json.Marshal(aggregateRequest{Query: query.Query, Field: field})
+		response, err := c.http.Post("/api/v1/certificates/aggregate", jsonData)
+		if err != nil {
+			return nil, err
+		}
+		var buckets []Bucket
+		if err := response.Json().Decode(&buckets); err != nil {
+			return nil, err
+		}
+		sortBuckets(buckets)
+		aggregation.Facets[field] = buckets
+	}
+	total, err := c.count(query.Query)
+	if err != nil {
+		return nil, err
+	}
+	aggregation.Total = total
+	if len(query.ExpiryBuckets) > 0 {
+		// Each bound is counted with a search, the buckets being the differences between consecutive bounds
+		now := time.Now()
+		previous := 0
+		for i, until := range expiryBounds(now, query.ExpiryBuckets) {
+			count, err := c.count(andQuery(query.Query, "notAfter before "+strconv.Quote(until.UTC().Format(time.RFC3339))))
+			if err != nil {
+				return nil, err
+			}
+			aggregation.Expiry = append(aggregation.Expiry, ExpiryBucket{Label: expiryLabel(i, query.ExpiryBuckets), Until: until, Count: count - previous})
+			previous = count
+		}
+		aggregation.Expiry = append(aggregation.Expiry, ExpiryBucket{Label: expiryLabel(len(query.ExpiryBuckets)+1, query.ExpiryBuckets), Count: total - previous})
+	}
+	return &aggregation, nil
+}
+
+func (c *Client) count(query string) (int, error) {
+	results, err := c.Search(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id"}, PageSize: 1, WithCount: true})
+	if err != nil {
+		return 0, err
+	}
+	return results.Count, nil
+}
+
+func (c *Client) aggregateLocal(query AggregationQuery, now time.Time) (*Aggregation, error) {
+	fields := append([]string{"_id", "notAfter"}, query.Fields...)
+	aggregation := Aggregation{Facets: make(map[string][]Bucket), Local: true}
+	counts := make(map[string]map[string]int)
+	for _, field := range query.Fields {
+		counts[field] = make(map[string]int)
+	}
+	bounds := expiryBounds(now, query.ExpiryBuckets)
+	expiry := make([]int, len(bounds)+1)
+	err := scanAs(c, horizon.CertificateSearchQuery{Query: query.Query, Fields: fields}, func(result *map[string]json.RawMessage) {
+		certificate := *result
+		aggregation.Total++
+		for _, field := range query.Fields {
+			counts[field][facetValue(certificate[field])]++
+		}
+		var notAfter int64
+		_ = json.Unmarshal(certificate["notAfter"], &notAfter)
+		bucket := len(bounds)
+		for i, until := range bounds {
+			if time.UnixMilli(notAfter).Before(until) {
+				bucket = i
+				break
+			}
+		}
+		expiry[bucket]++
+	})
+	if err != nil {
+		return nil, err
+	}
+	for field, values := range counts {
+		buckets := []Bucket{}
+		for value, count := range values {
+			buckets = append(buckets, Bucket{Value: value, Count: count})
+		}
+		sortBuckets(buckets)
+		aggregation.Facets[field] = buckets
+	}
+	if len(query.ExpiryBuckets) > 0 {
+		for i, count := range expiry {
+			bucket := ExpiryBucket{Label: expiryLabel(i, query.ExpiryBuckets), Count: count}
+			if i < len(bounds) {
+				bucket.Until = bounds[i]
+			}
+			aggregation.Expiry = append(aggregation.Expiry, bucket)
+		}
+	}
+	return &aggregation, nil
+}
+
+// expiryBounds returns now followed by the sorted upper bounds of the buckets
+func expiryBounds(now time.Time, buckets []time.Duration) []time.Time {
+	if len(buckets) == 0 {
+		return nil
+	}
+	sorted := append([]time.Duration{}, buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	bounds := []time.Time{now}
+	for _, d := range sorted {
+		bounds = append(bounds, now.Add(d))
+	}
+	return bounds
+}
+
+func expiryLabel(i int, buckets []time.Duration) string {
+	sorted := append([]time.Duration{}, buckets...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	switch {
+	case i == 0:
+		return "expired"
+	case i <= len(sorted):
+		return "within " + sorted[i-1].String()
+	}
+	return "after " + sorted[len(sorted)-1].String()
+}
+
+func facetValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var value string
+	if json.Unmarshal(raw, &value) == nil {
+		return value
+	}
+	return string(raw)
+}
+
+func sortBuckets(buckets []Bucket) {
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count == buckets[j].Count {
+			return buckets[i].Value < buckets[j].Value
+		}
+		return buckets[i].Count > buckets[j].Count
+	})
+}
+
+func andQuery(filter string, query string) string {
+	if filter == "" {
+		return query
+	}
+	return fmt.Sprintf("(%s) and %s", filter, query)
+}
@@ -0,0 +1,100 @@
+package certificates
+
+import (
+	"encoding/json"
+	gohttp "net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+var notAfterBefore = regexp.MustCompile(`notAfter before "([^"]+)"`)
+
+// newAggregationClient returns a client backed by a fake Horizon holding the given certificates, optionally supporting aggregations
+func newAggregationClient(t *testing.T, certificates []map[string]interface{}, aggregate bool) *Client {
+	return newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		switch r.URL.Path {
+		case "/api/v1/certificates/aggregate":
+			if !aggregate {
+				w.WriteHeader(404)
+				_, _ = w.Write([]byte("Not found"))
+				return
+			}
+			counts := make(map[string]int)
+			for _, certificate := range certificates {
+				counts[certificate[body["field"].(string)].(string)]++
+			}
+			var buckets []Bucket
+			for value, count := range counts {
+				buckets = append(buckets, Bucket{Value: value, Count: count})
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(buckets)
+		case "/api/v1/certificates/search":
+			matching := certificates
+			query, _ := body["query"].(string)
+			if match := notAfterBefore.FindStringSubmatch(query); match != nil {
+				until, _ := time.Parse(time.RFC3339, match[1])
+				matching = nil
+				for _, certificate := range certificates {
+					if certificate["notAfter"].(int64) < until.UnixMilli() {
+						matching = append(matching, certificate)
+					}
+				}
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": matching, "count": len(matching)})
+		}
+	})
+}
+
+func TestAggregate(t *testing.T) {
+	now := time.Now()
+	certificates := []map[string]interface{}{
+		{"_id": "a", "keyType": "rsa-2048", "notAfter": now.Add(-time.Hour).UnixMilli()},
+		{"_id": "b", "keyType": "rsa-2048", "notAfter": now.Add(24 * time.Hour).UnixMilli()},
+		{"_id": "c", "keyType": "ec-secp256r1", "notAfter": now.Add(48 * time.Hour).UnixMilli()},
+		{"_id": "d", "keyType": "rsa-2048", "notAfter": now.Add(1000 * time.Hour).UnixMilli()},
+	}
+	query := AggregationQuery{Fields: []string{"keyType"}, ExpiryBuckets: []time.Duration{72 * time.Hour, 30 * time.Hour}}
+	expected := "expired:1,within 30h0m0s:1,within 72h0m0s:1,after 72h0m0s:1"
+
+	for _, supported := range []bool{true, false} {
+		aggregation, err := newAggregationClient(t, certificates, supported).Aggregate(query)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if aggregation.Local == supported || aggregation.Total != 4 {
+			t.Errorf("unexpected aggregation mode or total, local %v, total %d", aggregation.Local, aggregation.Total)
+		}
+		if keyTypes := aggregation.Facets["keyType"]; len(keyTypes) != 2 || keyTypes[0] != (Bucket{"rsa-2048", 3}) {
+			t.Errorf("unexpected key type buckets %v", keyTypes)
+		}
+		var expiry []string
+		for _, bucket := range aggregation.Expiry {
+			expiry = append(expiry, bucket.Label+":"+strconv.Itoa(bucket.Count))
+		}
+		if strings.Join(expiry, ",") != expected {
+			t.Errorf("unexpected expiry buckets %v", expiry)
+		}
+	}
+}
+
+func TestAggregateReportsServerErrors(t *testing.T) {
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		if r.URL.Path == "/api/v1/certificates/aggregate" {
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("Internal error"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[]}`))
+	})
+	if _, err := client.Aggregate(AggregationQuery{Fields: []string{"keyType"}}); err == nil {
+		t.Error("a server error should not fall back to a local aggregation")
+	}
+}
@@ -0,0 +1,180 @@
+package certificates
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/requests"
+)
+
+// Field updates
+
+// FieldChange is the change of a single field. Its zero value leaves the field unchanged.
+type FieldChange struct {
+	set   bool
+	value *horizon.String
+}
+
+// SetField changes a field to the given value, horizon.Delete removing it
+func SetField(value *horizon.String) FieldChange {
+	return FieldChange{set: true, value: value}
+}
+
+// FieldUpdates lists the changes to apply to certificates.
+// Labels and metadata absent from the maps are left unchanged, those mapped to horizon.Delete are removed.
+type FieldUpdates struct {
+	Owner        FieldChange
+	Team         FieldChange
+	ContactEmail FieldChange
+	Labels       map[string]*horizon.String
+	Metadata     map[horizon.MetadataType]*horizon.String
+}
+
+type UpdateFieldsOptions struct {
+	// DryRun computes the changes without updating the certificates
+	DryRun bool
+	// RequesterComment is the justification displayed to approvers, if the update goes through a request
+	RequesterComment string
+}
+
+// FieldDiff is a field whose value changes. An empty Old or New value means the field is unset.
+type FieldDiff struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// FieldUpdateResult is the outcome of the update of a certificate.
+// Result is nil if the update was a dry run, if the certificate already had the requested values or if it failed.
+type FieldUpdateResult struct {
+	CertificateId string
+	Dn            string
+	Changes       []FieldDiff
+	Result        *ActionResult
+	Err           error
+}
+
+func stringValue(s *horizon.String) string {
+	if s == nil {
+		return ""
+	}
+	return s.String
+}
+
+// apply applies the updates to an update template, returning the fields whose value changes
+func (u FieldUpdates) apply(template *horizon.WebRAUpdateTemplate) ([]FieldDiff, error) {
+	var changes []FieldDiff
+	editor := template.Editor()
+	change := func(field string, old *horizon.String, value *horizon.String, edit func(string) *horizon.TemplateEditor) {
+		if stringValue(old) != stringValue(value) {
+			changes = append(changes, FieldDiff{Field: field, Old: stringValue(old), New: stringValue(value)})
+			edit(stringValue(value))
+		}
+	}
+	if u.Owner.set {
+		var old *horizon.String
+		if template.Owner != nil {
+			old = template.Owner.Value
+		}
+		change("owner", old, u.Owner.value, editor.SetOwner)
+	}
+	if u.Team.set {
+		var old *horizon.String
+		if template.Team != nil {
+			old = template.Team.Value
+		}
+		change("team", old, u.Team.value, editor.SetTeam)
+	}
+	if u.ContactEmail.set {
+		var old *horizon.String
+		if template.ContactEmail != nil {
+			old = template.ContactEmail.Value
+		}
+		change("contactEmail", old, u.ContactEmail.value, editor.SetContactEmail)
+	}
+	labels := make([]string, 0, len(u.Labels))
+	for label := range u.Labels {
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	for _, label := range labels {
+		var old *horizon.String
+		for _, element := range template.Labels {
+			if element.Label == label {
+				old = element.Value
+			}
+		}
+		change("labels."+label, old, u.Labels[label], func(value string) *horizon.TemplateEditor { return editor.SetLabel(label, value) })
+	}
+	metadata := make([]string, 0, len(u.Metadata))
+	for m := range u.Metadata {
+		metadata = append(metadata, string(m))
+	}
+	sort.Strings(metadata)
+	for _, m := range metadata {
+		var old *horizon.String
+		for _, element := range template.Metadata {
+			if string(element.Metadata) == m {
+				old = element.Value
+			}
+		}
+		change("metadata."+m, old, u.Metadata[horizon.MetadataType(m)], func(value string) *horizon.TemplateEditor {
+			return editor.SetMetadata(horizon.MetadataType(m), value)
+		})
+	}
+	return changes, editor.Err()
+}
+
+// UpdateCertificateFields changes the owner, team, contact email, labels and metadata of a certificate.
+// The update is performed directly if the principal is allowed to, or through an update request otherwise.
+// If the update fails, the result holding the attempted changes is returned along with the error, which is also set in Err.
+func (c *Client) UpdateCertificateFields(certificateId string, updates FieldUpdates, options UpdateFieldsOptions) (*FieldUpdateResult, error) {
+	template, err := requests.Init(c.http).GetUpdateTemplate(horizon.WebRAUpdateTemplateParams{CertificateId: certificateId})
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, errors.New("no update template returned for certificate " + certificateId)
+	}
+	changes, err := updates.apply(template)
+	if err != nil {
+		return nil, err
+	}
+	result := FieldUpdateResult{CertificateId: certificateId, Changes: changes}
+	if options.DryRun || len(changes) == 0 {
+		return &result, nil
+	}
+	result.Result, err = c.Update(horizon.WebRAUpdateRequestParams{
+		CertificateId:    certificateId,
+		Template:         template,
+		RequesterComment: options.RequesterComment,
+	})
+	if err != nil {
+		result.Err = err
+		return &result, err
+	}
+	return &result, nil
+}
+
+// UpdateCertificateFieldsByQuery applies the updates to every certificate matching the HPQL query.
+// A certificate failing to update does not stop the others, its error being returned in its result.
+func (c *Client) UpdateCertificateFieldsByQuery(query string, updates FieldUpdates, options UpdateFieldsOptions) ([]FieldUpdateResult, error) {
+	var certificates []horizon.CertificateSearchResult
+	err := c.scan(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id", "dn"}}, func(result *horizon.CertificateSearchResult) {
+		certificates = append(certificates, *result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]FieldUpdateResult, 0, len(certificates))
+	for _, certificate := range certificates {
+		result, err := c.UpdateCertificateFields(certificate.Id, updates, options)
+		if result == nil {
+			result = &FieldUpdateResult{CertificateId: certificate.Id, Err: err}
+		}
+		result.Dn = certificate.Dn
+		results = append(results, *result)
+	}
+	return results, nil
+}
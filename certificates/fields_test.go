@@ -0,0 +1,91 @@
+package certificates
+
+import (
+	"encoding/json"
+	gohttp "net/http"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestUpdateCertificateFields(t *testing.T) {
+	var submitted []horizon.WebRAUpdateRequest
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/certificates/search":
+			_, _ = w.Write([]byte(`{"results": [{"_id": "a", "dn": "CN=a", "permissions": {"requestUpdate": true}}, {"_id": "b", "dn": "CN=b", "permissions": {"requestUpdate": true}}]}`))
+		case "/api/v1/requests/template":
+			var request horizon.WebRAUpdateRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			owner := "alice"
+			if request.CertificateId == "b" {
+				owner = "bob"
+			}
+			request.Template = &horizon.WebRAUpdateTemplate{
+				Owner:  &horizon.OwnerElement{Value: &horizon.String{String: owner}, Editable: true},
+				Team:   &horizon.TeamElement{Value: &horizon.String{String: "old"}, Editable: true},
+				Labels: []horizon.LabelElement{{Label: "env", Value: &horizon.String{String: "prod"}, Editable: true}},
+			}
+			_ = json.NewEncoder(w).Encode(request)
+		case "/api/v1/requests/submit":
+			var request horizon.WebRAUpdateRequest
+			_ = json.NewDecoder(r.Body).Decode(&request)
+			if request.CertificateId == "a" {
+				w.WriteHeader(403)
+				_, _ = w.Write([]byte(`{"error": "SEC-AUTH-001", "message": "denied"}`))
+				return
+			}
+			submitted = append(submitted, request)
+			request.Id = "request"
+			_ = json.NewEncoder(w).Encode(request)
+		}
+	})
+
+	updates := FieldUpdates{
+		Owner:  SetField(&horizon.String{String: "alice"}),
+		Team:   SetField(horizon.Delete),
+		Labels: map[string]*horizon.String{"env": {String: "prod"}},
+	}
+	results, err := client.UpdateCertificateFieldsByQuery("owner is bob", updates, UpdateFieldsOptions{DryRun: true})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(submitted) != 0 {
+		t.Error("a dry run should not submit any request")
+	}
+	if len(results) != 2 || len(results[0].Changes) != 1 || results[0].Changes[0] != (FieldDiff{Field: "team", Old: "old"}) {
+		t.Errorf("unexpected changes for a: %v", results[0].Changes)
+	}
+	if len(results[1].Changes) != 2 || results[1].Changes[0] != (FieldDiff{Field: "owner", Old: "bob", New: "alice"}) || results[1].Dn != "CN=b" {
+		t.Errorf("unexpected changes for b: %v", results[1].Changes)
+	}
+
+	result, err := client.UpdateCertificateFields("b", updates, UpdateFieldsOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Result == nil || result.Result.Request.GetId() != "request" || len(submitted) != 1 {
+		t.Fatal("an update request should have been submitted")
+	}
+	template := submitted[0].Template
+	if template.Owner.Value.String != "alice" || template.Team.Value != horizon.Delete {
+		t.Errorf("unexpected submitted template %v %v", template.Owner.Value, template.Team.Value)
+	}
+
+	if _, err := client.UpdateCertificateFields("a", FieldUpdates{ContactEmail: SetField(&horizon.String{String: "x@y.z"})}, UpdateFieldsOptions{}); err == nil {
+		t.Error("updating a field absent from the template should fail")
+	}
+
+	// A failed update keeps the changes that were attempted
+	results, err = client.UpdateCertificateFieldsByQuery("owner is bob", updates, UpdateFieldsOptions{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if results[0].Err == nil || len(results[0].Changes) != 1 || results[0].Dn != "CN=a" || results[0].Result != nil {
+		t.Errorf("the failed update of a should be reported with its changes, got %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].Result == nil {
+		t.Errorf("b should be updated, got %v", results[1].Err)
+	}
+}
@@ -0,0 +1,367 @@
+package certificates
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Inventory cache
+
+// InventorySnapshot is the persisted state of an Inventory
+type InventorySnapshot struct {
+	Certificates map[string]horizon.CertificateSearchResult `json:"certificates"`
+	// LastModificationDate is the most recent modification date synced, in epoch milliseconds
+	LastModificationDate int64 `json:"lastModificationDate"`
+	// SeenAtLastModificationDate lists the certificates already synced whose modification date is LastModificationDate
+	SeenAtLastModificationDate []string  `json:"seenAtLastModificationDate,omitempty"`
+	LastSync                   time.Time `json:"lastSync"`
+	LastFullSync               time.Time `json:"lastFullSync"`
+}
+
+// InventoryStore persists the inventory between runs. Load returns a nil snapshot if none was saved yet.
+type InventoryStore interface {
+	Load() (*InventorySnapshot, error)
+	Save(snapshot *InventorySnapshot) error
+}
+
+type MemoryInventoryStore struct {
+	mutex    sync.Mutex
+	snapshot *InventorySnapshot
+}
+
+func (m *MemoryInventoryStore) Load() (*InventorySnapshot, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.snapshot, nil
+}
+
+func (m *MemoryInventoryStore) Save(snapshot *InventorySnapshot) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.snapshot = snapshot
+	return nil
+}
+
+// FileInventoryStore persists the inventory as a JSON file, replaced atomically on each save
+type FileInventoryStore struct {
+	Path string
+}
+
+func (f *FileInventoryStore) Load() (*InventorySnapshot, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var snapshot InventorySnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid inventory file %s: %s", f.Path, err.Error())
+	}
+	return &snapshot, nil
+}
+
+func (f *FileInventoryStore) Save(snapshot *InventorySnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	return horizon.WriteFileAtomic(f.Path, data, 0600)
+}
+
+type InventoryOptions struct {
+	// Query is an optional HPQL filter restricting the certificates cached
+	Query string
+	// Store defaults to a MemoryInventoryStore
+	Store InventoryStore
+	// FullSyncInterval is the interval between two full syncs, which detect the certificates deleted from Horizon
+	// or no longer matching the query. Defaults to 24 hours.
+	FullSyncInterval time.Duration
+	// PageSize of the searches, defaults to 100
+	PageSize int
+}
+
+// SyncResult counts the changes applied to the inventory by a sync
+type SyncResult struct {
+	Full    bool
+	Added   int
+	Updated int
+	Revoked int
+	Removed int
+}
+
+// Freshness tells how up to date the inventory is
+type Freshness struct {
+	Count        int
+	LastSync     time.Time
+	LastFullSync time.Time
+	// LastModification is the most recent modification of a certificate synced
+	LastModification time.Time
+}
+
+// Age returns the time elapsed since the last sync, as of now
+func (f Freshness) Age(now time.Time) time.Duration {
+	return now.Sub(f.LastSync)
+}
+
+// Inventory is a local copy of the Horizon certificates, answering lookups offline. It is safe for concurrent use.
+type Inventory struct {
+	client  *Client
+	options InventoryOptions
+	now     func() time.Time
+	// syncMutex serializes the syncs, each one starting from the snapshot of the previous one
+	syncMutex sync.Mutex
+	mutex     sync.RWMutex
+	snapshot  *InventorySnapshot
+	// indexes map a lowercase thumbprint, DN or SAN value to certificate IDs
+	byThumbprint map[string]string
+	byDn         map[string][]string
+	bySan        map[string][]string
+}
+
+var inventoryFields = []string{
+	"_id", "module", "dn", "serial", "issuer", "profile", "owner", "team", "holderId", "notBefore", "notAfter", "thumbprint",
+	"revoked", "revocationDate", "revocationReason", "subjectAlternateNames", "lastModificationDate",
+}
+
+// NewInventory loads the inventory from its store. Call Sync to fetch the certificates from Horizon.
+func (c *Client) NewInventory(options InventoryOptions) (*Inventory, error) {
+	if options.Store == nil {
+		options.Store = &MemoryInventoryStore{}
+	}
+	if options.FullSyncInterval <= 0 {
+		options.FullSyncInterval = 24 * time.Hour
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = 100
+	}
+	snapshot, err := options.Store.Load()
+	if err != nil {
+		return nil, err
+	}
+	if snapshot == nil {
+		snapshot = &InventorySnapshot{}
+	}
+	if snapshot.Certificates == nil {
+		snapshot.Certificates = make(map[string]horizon.CertificateSearchResult)
+	}
+	inventory := Inventory{client: c, options: options, now: time.Now, snapshot: snapshot}
+	inventory.index()
+	return &inventory, nil
+}
+
+// Sync fetches the certificates modified since the last sync. A full sync is performed instead on the first sync
+// and once FullSyncInterval has elapsed since the last one.
+func (i *Inventory) Sync() (*SyncResult, error) {
+	i.mutex.RLock()
+	due := i.snapshot.LastFullSync.IsZero() || i.now().Sub(i.snapshot.LastFullSync) >= i.options.FullSyncInterval
+	i.mutex.RUnlock()
+	if due {
+		return i.FullSync()
+	}
+	return i.sync(false)
+}
+
+// FullSync fetches every certificate matching the query and drops the cached certificates that no longer do
+func (i *Inventory) FullSync() (*SyncResult, error) {
+	return i.sync(true)
+}
+
+// sync pages through the certificates by keyset: each search restarts from the last modification date seen, skipping the
+// certificates already synced, so that certificates modified while syncing do not shift the pages
+func (i *Inventory) sync(full bool) (*SyncResult, error) {
+	i.syncMutex.Lock()
+	defer i.syncMutex.Unlock()
+	i.mutex.RLock()
+	previous := i.snapshot
+	i.mutex.RUnlock()
+
+	now := i.now()
+	result := SyncResult{Full: full}
+	snapshot := InventorySnapshot{
+		Certificates:               make(map[string]horizon.CertificateSearchResult, len(previous.Certificates)),
+		LastModificationDate:       previous.LastModificationDate,
+		SeenAtLastModificationDate: previous.SeenAtLastModificationDate,
+		LastSync:                   now,
+		LastFullSync:               previous.LastFullSync,
+	}
+	// The keyset is the last modification date seen and the certificates seen at that date
+	var keysetDate int64
+	keysetSeen := make(map[string]bool)
+	if full {
+		snapshot.LastFullSync = now
+	} else {
+		for id, certificate := range previous.Certificates {
+			snapshot.Certificates[id] = certificate
+		}
+		keysetDate = previous.LastModificationDate
+		for _, id := range previous.SeenAtLastModificationDate {
+			keysetSeen[id] = true
+		}
+	}
+	// A certificate modified while syncing is found again, it is only counted once
+	synced := make(map[string]bool)
+	for page := 1; ; {
+		from := keysetDate
+		query := i.options.Query
+		if from > 0 {
+			query = andQuery(query, fmt.Sprintf(`lastModificationDate after "%s"`, time.UnixMilli(from-1).UTC().Format("2006-01-02T15:04:05.000Z07:00")))
+		}
+		results, err := i.client.Search(horizon.CertificateSearchQuery{
+			Query:     query,
+			Fields:    inventoryFields,
+			SortedBy:  []horizon.SortFields{{Element: "lastModificationDate", Order: horizon.Ascendant}, {Element: "_id", Order: horizon.Ascendant}},
+			PageIndex: page,
+			PageSize:  i.options.PageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, certificate := range results.Results {
+			if certificate.LastModificationDate < keysetDate || (certificate.LastModificationDate == keysetDate && keysetSeen[certificate.Id]) {
+				continue
+			}
+			if certificate.LastModificationDate > keysetDate {
+				keysetDate = certificate.LastModificationDate
+				keysetSeen = make(map[string]bool)
+			}
+			keysetSeen[certificate.Id] = true
+			old, cached := previous.Certificates[certificate.Id]
+			switch {
+			case synced[certificate.Id]:
+			case !cached:
+				result.Added++
+			case certificate.Revoked && !old.Revoked:
+				result.Revoked++
+			case certificate.LastModificationDate != old.LastModificationDate:
+				result.Updated++
+			}
+			synced[certificate.Id] = true
+			snapshot.Certificates[certificate.Id] = certificate
+			if certificate.LastModificationDate > snapshot.LastModificationDate {
+				snapshot.LastModificationDate = certificate.LastModificationDate
+				snapshot.SeenAtLastModificationDate = nil
+			}
+			if certificate.LastModificationDate == snapshot.LastModificationDate {
+				snapshot.SeenAtLastModificationDate = append(snapshot.SeenAtLastModificationDate, certificate.Id)
+			}
+		}
+		if !results.HasMore || len(results.Results) == 0 {
+			break
+		}
+		if keysetDate == from {
+			// The whole page was modified at the same date, the next one may hold more certificates of that date
+			page++
+		} else {
+			page = 1
+		}
+	}
+	if full {
+		for id := range previous.Certificates {
+			if _, found := snapshot.Certificates[id]; !found {
+				result.Removed++
+			}
+		}
+	}
+	if err := i.options.Store.Save(&snapshot); err != nil {
+		return nil, err
+	}
+	i.mutex.Lock()
+	i.snapshot = &snapshot
+	i.index()
+	i.mutex.Unlock()
+	return &result, nil
+}
+
+// index rebuilds the lookup indexes, the caller must hold the write lock
+func (i *Inventory) index() {
+	i.byThumbprint = make(map[string]string, len(i.snapshot.Certificates))
+	i.byDn = make(map[string][]string)
+	i.bySan = make(map[string][]string)
+	for id, certificate := range i.snapshot.Certificates {
+		if certificate.Thumbprint != "" {
+			i.byThumbprint[strings.ToLower(certificate.Thumbprint)] = id
+		}
+		dn := strings.ToLower(certificate.Dn)
+		i.byDn[dn] = append(i.byDn[dn], id)
+		for _, san := range certificate.SubjectAlternateNames {
+			value := strings.ToLower(san.Value)
+			i.bySan[value] = append(i.bySan[value], id)
+		}
+	}
+}
+
+// certificates returns the cached certificates with the given IDs, sorted by notAfter. The caller must hold the read lock.
+func (i *Inventory) certificates(ids []string) []horizon.CertificateSearchResult {
+	certificates := make([]horizon.CertificateSearchResult, 0, len(ids))
+	for _, id := range ids {
+		certificates = append(certificates, i.snapshot.Certificates[id])
+	}
+	sort.Slice(certificates, func(a, b int) bool { return certificates[a].NotAfter < certificates[b].NotAfter })
+	return certificates
+}
+
+// Freshness returns the number of cached certificates and the dates of the last syncs
+func (i *Inventory) Freshness() Freshness {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	freshness := Freshness{
+		Count:        len(i.snapshot.Certificates),
+		LastSync:     i.snapshot.LastSync,
+		LastFullSync: i.snapshot.LastFullSync,
+	}
+	if i.snapshot.LastModificationDate > 0 {
+		freshness.LastModification = time.UnixMilli(i.snapshot.LastModificationDate)
+	}
+	return freshness
+}
+
+// ByThumbprint returns the cached certificate with the given SHA-256 thumbprint
+func (i *Inventory) ByThumbprint(thumbprint string) (*horizon.CertificateSearchResult, bool) {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	id, found := i.byThumbprint[strings.ToLower(thumbprint)]
+	if !found {
+		return nil, false
+	}
+	certificate := i.snapshot.Certificates[id]
+	return &certificate, true
+}
+
+// ByDn returns the cached certificates with the given DN, compared case-insensitively
+func (i *Inventory) ByDn(dn string) []horizon.CertificateSearchResult {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.certificates(i.byDn[strings.ToLower(dn)])
+}
+
+// BySAN returns the cached certificates having a SAN of any type with the given value, compared case-insensitively
+func (i *Inventory) BySAN(value string) []horizon.CertificateSearchResult {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	return i.certificates(i.bySan[strings.ToLower(value)])
+}
+
+// ExpiringWithin returns the cached certificates that are not revoked and expire within the duration, from the soonest to expire
+func (i *Inventory) ExpiringWithin(d time.Duration) []horizon.CertificateSearchResult {
+	i.mutex.RLock()
+	defer i.mutex.RUnlock()
+	now := i.now()
+	var ids []string
+	for id, certificate := range i.snapshot.Certificates {
+		notAfter := time.UnixMilli(int64(certificate.NotAfter))
+		if !certificate.Revoked && notAfter.After(now) && !notAfter.After(now.Add(d)) {
+			ids = append(ids, id)
+		}
+	}
+	return i.certificates(ids)
+}
@@ -0,0 +1,163 @@
+package certificates
+
+import (
+	"encoding/json"
+	gohttp "net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestInventory(t *testing.T) {
+	now := time.Now()
+	in := func(d time.Duration) int { return int(now.Add(d).UnixMilli()) }
+	var page []horizon.CertificateSearchResult
+	var queries []string
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var query horizon.CertificateSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		queries = append(queries, query.Query)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: page})
+	})
+
+	store := &FileInventoryStore{Path: filepath.Join(t.TempDir(), "inventory.json")}
+	inventory, err := client.NewInventory(InventoryOptions{Query: "module is webra", Store: store})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	page = []horizon.CertificateSearchResult{
+		{Id: "a", Dn: "CN=a", Thumbprint: "AA", NotAfter: in(10 * 24 * time.Hour), LastModificationDate: 1000,
+			SubjectAlternateNames: horizon.SubjectAlternateNames{{SanType: horizon.SANDnsName, Value: "a.example.com"}}},
+		{Id: "b", Dn: "CN=b", Thumbprint: "bb", NotAfter: in(100 * 24 * time.Hour), LastModificationDate: 2000},
+		{Id: "c", Dn: "CN=c", Thumbprint: "cc", NotAfter: in(5 * 24 * time.Hour), LastModificationDate: 2000},
+	}
+	result, err := inventory.Sync()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !result.Full || result.Added != 3 || strings.Contains(queries[0], "lastModificationDate") {
+		t.Errorf("the first sync should be full, got %+v with query %s", result, queries[0])
+	}
+
+	// Incremental sync: c is repeated at the cursor date, b is revoked and d is new
+	page = []horizon.CertificateSearchResult{
+		{Id: "c", Dn: "CN=c", Thumbprint: "cc", NotAfter: in(5 * 24 * time.Hour), LastModificationDate: 2000},
+		{Id: "b", Dn: "CN=b", Thumbprint: "bb", NotAfter: in(100 * 24 * time.Hour), LastModificationDate: 3000, Revoked: true},
+		{Id: "d", Dn: "CN=a", Thumbprint: "dd", NotAfter: in(20 * 24 * time.Hour), LastModificationDate: 3000},
+	}
+	result, err = inventory.Sync()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Full || result.Added != 1 || result.Revoked != 1 || result.Updated != 0 {
+		t.Errorf("unexpected incremental sync %+v", result)
+	}
+	if !strings.HasPrefix(queries[1], `(module is webra) and lastModificationDate after "1970-01-01T00:00:01.999Z"`) {
+		t.Errorf("unexpected incremental query %s", queries[1])
+	}
+
+	// Reload from the store and look up offline
+	inventory, err = client.NewInventory(InventoryOptions{Store: store})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if certificate, found := inventory.ByThumbprint("AA"); !found || certificate.Id != "a" {
+		t.Error("a should be found by thumbprint")
+	}
+	if certificates := inventory.ByDn("cn=A"); len(certificates) != 2 || certificates[0].Id != "a" {
+		t.Errorf("unexpected DN lookup %v", certificates)
+	}
+	if certificates := inventory.BySAN("A.example.com"); len(certificates) != 1 {
+		t.Errorf("unexpected SAN lookup %v", certificates)
+	}
+	if certificates := inventory.ExpiringWithin(15 * 24 * time.Hour); len(certificates) != 2 || certificates[0].Id != "c" {
+		t.Errorf("unexpected expiring certificates %v", certificates)
+	}
+	freshness := inventory.Freshness()
+	if freshness.Count != 4 || freshness.LastModification.UnixMilli() != 3000 || freshness.Age(time.Now()) > time.Minute {
+		t.Errorf("unexpected freshness %+v", freshness)
+	}
+
+	// A full sync drops the deleted certificates
+	page = page[1:]
+	result, err = inventory.FullSync()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Removed != 2 || inventory.Freshness().Count != 2 {
+		t.Errorf("unexpected full sync %+v", result)
+	}
+	if _, found := inventory.ByThumbprint("aa"); found {
+		t.Error("a should have been removed")
+	}
+}
+
+var modifiedAfter = regexp.MustCompile(`lastModificationDate after "([^"]+)"`)
+
+func TestInventoryKeysetPaging(t *testing.T) {
+	current := []horizon.CertificateSearchResult{
+		{Id: "a", LastModificationDate: 1000},
+		{Id: "b", LastModificationDate: 2000},
+		{Id: "c", LastModificationDate: 2000},
+		{Id: "d", LastModificationDate: 2000},
+		{Id: "e", LastModificationDate: 2000},
+		{Id: "f", LastModificationDate: 2000},
+		{Id: "g", LastModificationDate: 3000},
+	}
+	searches := 0
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var query horizon.CertificateSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		var after int64 = -1
+		if match := modifiedAfter.FindStringSubmatch(query.Query); match != nil {
+			date, _ := time.Parse("2006-01-02T15:04:05.000Z07:00", match[1])
+			after = date.UnixMilli()
+		}
+		var matching []horizon.CertificateSearchResult
+		for _, certificate := range current {
+			if certificate.LastModificationDate > after {
+				matching = append(matching, certificate)
+			}
+		}
+		// Ties are only returned in a stable order when sorted by _id
+		stable := len(query.SortedBy) > 1 && query.SortedBy[1].Element == "_id"
+		sort.SliceStable(matching, func(i, j int) bool {
+			if matching[i].LastModificationDate != matching[j].LastModificationDate {
+				return matching[i].LastModificationDate < matching[j].LastModificationDate
+			}
+			return (matching[i].Id < matching[j].Id) == (stable || searches%2 == 0)
+		})
+		start := (query.PageIndex - 1) * query.PageSize
+		end := start + query.PageSize
+		if start > len(matching) {
+			start = len(matching)
+		}
+		if end > len(matching) {
+			end = len(matching)
+		}
+		searches++
+		if searches == 1 {
+			// a is modified once the first page was served, moving it to the end of the results
+			current[0].LastModificationDate = 4000
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: matching[start:end], HasMore: end < len(matching)})
+	})
+	inventory, err := client.NewInventory(InventoryOptions{PageSize: 2})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	result, err := inventory.FullSync()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Added != 7 || inventory.Freshness().Count != 7 || inventory.Freshness().LastModification.UnixMilli() != 4000 {
+		t.Errorf("every certificate should be synced once, got %+v", result)
+	}
+}
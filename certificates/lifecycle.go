@@ -0,0 +1,129 @@
+package certificates
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/requests"
+)
+
+// Lifecycle operations
+
+var ActionNotPermittedError = errors.New("action not permitted on certificate")
+var CertificateNotFoundError = errors.New("certificate not found")
+
+// ActionResult is the outcome of a lifecycle operation.
+// Operations are always submitted through the request API, so that the requester comment, contact and labels are kept:
+// Horizon completes the request immediately when the principal may perform the action directly, Certificate being then set
+// to the certificate as it is after the action, and waits for an approval otherwise.
+type ActionResult struct {
+	Certificate *horizon.Certificate
+	Request     horizon.Request
+}
+
+// IsDirect returns true if the operation was performed without waiting for an approval
+func (r *ActionResult) IsDirect() bool {
+	return r.Request != nil && r.Request.GetStatus() == horizon.Completed
+}
+
+// Permissions returns the permissions of the current principal on a certificate, given by ID or PEM
+func (c *Client) Permissions(certificateId string, certificatePem string) (*horizon.CertificateSearchResult, error) {
+	var query string
+	switch {
+	case certificateId != "":
+		query = "_id equals " + strconv.Quote(certificateId)
+	case certificatePem != "":
+		certificate, err := horizon.ParseCertificatePem(certificatePem)
+		if err != nil {
+			return nil, err
+		}
+		query = "thumbprint equals " + strconv.Quote(horizon.Thumbprint(certificate))
+	default:
+		return nil, errors.New("either a certificate ID or PEM is required")
+	}
+	results, err := c.Search(horizon.CertificateSearchQuery{Query: query, PageSize: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) == 0 {
+		return nil, fmt.Errorf("%w: %s", CertificateNotFoundError, query)
+	}
+	return &results.Results[0], nil
+}
+
+// submit submits the request of an operation and fetches the certificate if Horizon completed it immediately
+func (c *Client) submit(certificateId string, submit func(client *requests.Client) (horizon.Request, error)) (*ActionResult, error) {
+	request, err := submit(requests.Init(c.http))
+	if err != nil {
+		return nil, err
+	}
+	result := ActionResult{Request: request}
+	if result.IsDirect() {
+		if result.Certificate, err = c.Get(certificateId); err != nil {
+			return &result, err
+		}
+	}
+	return &result, nil
+}
+
+// Revoke revokes the certificate directly if the principal is allowed to, or submits a revocation request waiting for an approval otherwise
+func (c *Client) Revoke(params horizon.WebRARevokeRequestParams) (*ActionResult, error) {
+	permissions, err := c.Permissions(params.CertificateId, params.CertificatePEM)
+	if err != nil {
+		return nil, err
+	}
+	if !permissions.Permissions.Revoke && !permissions.Permissions.RequestRevoke {
+		return nil, fmt.Errorf("%w: revoke", ActionNotPermittedError)
+	}
+	return c.submit(permissions.Id, func(client *requests.Client) (horizon.Request, error) {
+		return client.NewRevokeRequest(params)
+	})
+}
+
+// Update updates the certificate directly if the principal is allowed to, or submits an update request waiting for an approval otherwise
+func (c *Client) Update(params horizon.WebRAUpdateRequestParams) (*ActionResult, error) {
+	permissions, err := c.Permissions(params.CertificateId, params.CertificatePEM)
+	if err != nil {
+		return nil, err
+	}
+	if !permissions.Permissions.Update && !permissions.Permissions.RequestUpdate {
+		return nil, fmt.Errorf("%w: update", ActionNotPermittedError)
+	}
+	return c.submit(permissions.Id, func(client *requests.Client) (horizon.Request, error) {
+		return client.NewUpdateRequest(params)
+	})
+}
+
+// Migrate migrates the certificate directly if the principal is allowed to, or submits a migration request waiting for an approval otherwise
+func (c *Client) Migrate(params horizon.WebRAMigrateRequestParams) (*ActionResult, error) {
+	permissions, err := c.Permissions(params.CertificateId, params.CertificatePEM)
+	if err != nil {
+		return nil, err
+	}
+	if !permissions.Permissions.Migrate && !permissions.Permissions.RequestMigrate {
+		return nil, fmt.Errorf("%w: migrate", ActionNotPermittedError)
+	}
+	return c.submit(permissions.Id, func(client *requests.Client) (horizon.Request, error) {
+		return client.NewMigrateRequest(params)
+	})
+}
+
+// Recover recovers the key material of an escrowed certificate.
+// The PKCS#12 is returned in the request: with the recover permission the request is completed immediately, with
+// requestRecover it waits for approval. Certificate is never set in the result.
+func (c *Client) Recover(params horizon.WebRARecoverRequestParams) (*ActionResult, error) {
+	permissions, err := c.Permissions(params.CertificateId, params.CertificatePEM)
+	if err != nil {
+		return nil, err
+	}
+	if !permissions.Permissions.Recover && !permissions.Permissions.RequestRecover {
+		return nil, fmt.Errorf("%w: recover", ActionNotPermittedError)
+	}
+	request, err := requests.Init(c.http).NewRecoverRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	return &ActionResult{Request: request}, nil
+}
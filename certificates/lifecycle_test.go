@@ -0,0 +1,78 @@
+package certificates
+
+import (
+	"encoding/json"
+	"errors"
+	gohttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/http"
+)
+
+// newTestClient returns a client backed by a fake Horizon served by the handler
+func newTestClient(t *testing.T, handler gohttp.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	endpoint, _ := url.Parse(server.URL)
+	baseClient := http.Client{}
+	baseClient.SetHttpClient(nil).SetBaseUrl(*endpoint)
+	return &Client{http: &baseClient}
+}
+
+// newLifecycleClient returns a client backed by a fake Horizon granting the given permissions on every certificate.
+// Revocations are completed immediately, as Horizon does when the principal has the revoke permission.
+func newLifecycleClient(t *testing.T, permissions string) (*Client, *[]string, *[]map[string]interface{}) {
+	var calls []string
+	var submitted []map[string]interface{}
+	return newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		calls = append(calls, r.URL.Path)
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/certificates/search":
+			_, _ = w.Write([]byte(`{"results": [{"_id": "id", "permissions": ` + permissions + `}]}`))
+		case "/api/v1/requests/submit":
+			submitted = append(submitted, body)
+			body["_id"] = "request"
+			body["status"] = horizon.Pending
+			if body["workflow"] == string(horizon.Revoke) {
+				body["status"] = horizon.Completed
+			}
+			_ = json.NewEncoder(w).Encode(body)
+		case "/api/v1/certificates/id":
+			_ = json.NewEncoder(w).Encode(horizon.CertificateResponse{Certificate: horizon.Certificate{Id: "id", Revoked: true}})
+		default:
+			w.WriteHeader(gohttp.StatusNotFound)
+		}
+	}), &calls, &submitted
+}
+
+func TestLifecyclePaths(t *testing.T) {
+	client, calls, submitted := newLifecycleClient(t, `{"revoke": true, "requestUpdate": true}`)
+	result, err := client.Revoke(horizon.WebRARevokeRequestParams{CertificateId: "id", RevocationReason: horizon.Superseded, RequesterComment: "superseded by a new key", Contact: "pki@example.org"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !result.IsDirect() || !result.Certificate.Revoked || (*calls)[1] != "/api/v1/requests/submit" {
+		t.Errorf("revocation should be direct, called %v", *calls)
+	}
+	if (*submitted)[0]["requesterComment"] != "superseded by a new key" || (*submitted)[0]["contact"] != "pki@example.org" {
+		t.Errorf("the comment and contact should be kept, got %v", (*submitted)[0])
+	}
+
+	result, err = client.Update(horizon.WebRAUpdateRequestParams{CertificateId: "id"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.IsDirect() || result.Certificate != nil || result.Request.GetId() != "request" {
+		t.Error("update should wait for an approval")
+	}
+
+	if _, err := client.Migrate(horizon.WebRAMigrateRequestParams{CertificateId: "id"}); !errors.Is(err, ActionNotPermittedError) {
+		t.Errorf("migration should not be permitted, got %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package certificates
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Lookups
+
+// GetByPem returns the certificate matching a PEM, looked up by its thumbprint computed locally
+func (c *Client) GetByPem(certificatePem string) (*horizon.Certificate, error) {
+	certificate, err := horizon.ParseCertificatePem(certificatePem)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetByThumbprint(horizon.Thumbprint(certificate))
+}
+
+// GetByThumbprint returns the certificate with the given SHA-256 thumbprint
+func (c *Client) GetByThumbprint(thumbprint string) (*horizon.Certificate, error) {
+	return c.findOne("thumbprint equals " + strconv.Quote(thumbprint))
+}
+
+// GetBySerialAndIssuer returns the certificate with the given serial number issued by the given issuer DN
+func (c *Client) GetBySerialAndIssuer(serial string, issuer string) (*horizon.Certificate, error) {
+	return c.findOne(fmt.Sprintf("serial equals %s and issuer equals %s", strconv.Quote(serial), strconv.Quote(issuer)))
+}
+
+// GetByPublicKeyThumbprint returns every certificate sharing the public key with the given thumbprint, e.g. to revoke them after a key compromise
+func (c *Client) GetByPublicKeyThumbprint(publicKeyThumbprint string) ([]*horizon.Certificate, error) {
+	query := "publicKeyThumbprint equals " + strconv.Quote(publicKeyThumbprint)
+	var ids []string
+	err := c.scan(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id"}}, func(result *horizon.CertificateSearchResult) {
+		ids = append(ids, result.Id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	var certificates []*horizon.Certificate
+	for _, id := range ids {
+		certificate, err := c.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, fmt.Errorf("%w: %s", CertificateNotFoundError, query)
+	}
+	return certificates, nil
+}
+
+func (c *Client) findOne(query string) (*horizon.Certificate, error) {
+	results, err := c.Search(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id"}, PageSize: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) == 0 {
+		return nil, fmt.Errorf("%w: %s", CertificateNotFoundError, query)
+	}
+	return c.Get(results.Results[0].Id)
+}
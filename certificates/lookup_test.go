@@ -0,0 +1,62 @@
+package certificates
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	gohttp "net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestLookups(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "lookup"}, NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+	der, _ := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	certificate, _ := x509.ParseCertificate(der)
+	certificatePem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	// Searches match the thumbprint of the certificate and its public key thumbprint, shared by two certificates
+	known := map[string][]string{
+		`thumbprint equals "` + horizon.Thumbprint(certificate) + `"`:                   {"a"},
+		`serial equals "01" and issuer equals "CN=lookup"`:                              {"a"},
+		`publicKeyThumbprint equals "` + horizon.PublicKeyThumbprint(certificate) + `"`: {"a", "b"},
+	}
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/certificates/")
+			_ = json.NewEncoder(w).Encode(horizon.CertificateResponse{Certificate: horizon.Certificate{Id: id}})
+			return
+		}
+		var query horizon.CertificateSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		var results []horizon.CertificateSearchResult
+		for _, id := range known[query.Query] {
+			results = append(results, horizon.CertificateSearchResult{Id: id})
+		}
+		_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: results})
+	})
+
+	if found, err := client.GetByPem(certificatePem); err != nil || found.Id != "a" {
+		t.Errorf("certificate should be found by PEM, got %v", err)
+	}
+	if found, err := client.GetBySerialAndIssuer("01", "CN=lookup"); err != nil || found.Id != "a" {
+		t.Errorf("certificate should be found by serial and issuer, got %v", err)
+	}
+	if found, err := client.GetByPublicKeyThumbprint(horizon.PublicKeyThumbprint(certificate)); err != nil || len(found) != 2 {
+		t.Errorf("both certificates sharing the key should be found, got %v", err)
+	}
+	if _, err := client.GetByThumbprint("unknown"); !errors.Is(err, CertificateNotFoundError) {
+		t.Errorf("expected a not found error, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package certificates
+
+import (
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Reconciliation of deployed certificates
+
+type DriftStatus string
+
+const (
+	// DriftUnknown certificates are not in the Horizon inventory
+	DriftUnknown DriftStatus = "unknown"
+	DriftCurrent DriftStatus = "current"
+	DriftRevoked DriftStatus = "revoked"
+	// DriftRenewedElsewhere certificates were renewed, the renewed certificate not being the one deployed
+	DriftRenewedElsewhere DriftStatus = "renewed-elsewhere"
+	DriftExpired          DriftStatus = "expired"
+)
+
+// DeployedCertificate is a leaf certificate found on disk and its state in Horizon.
+// Horizon is nil if the certificate is unknown, RenewedCertificateId is the ID of its renewal if it was renewed.
+type DeployedCertificate struct {
+	Path                 string               `json:"path"`
+	Dn                   string               `json:"dn"`
+	Thumbprint           string               `json:"thumbprint"`
+	NotAfter             time.Time            `json:"notAfter"`
+	Status               DriftStatus          `json:"status"`
+	RenewedCertificateId string               `json:"renewedCertificateId,omitempty"`
+	Horizon              *horizon.Certificate `json:"-"`
+}
+
+// FileError is a file that could not be read or looked up
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e FileError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Path  string `json:"path"`
+		Error string `json:"error"`
+	}{e.Path, e.Err.Error()})
+}
+
+// DriftReport lists the deployed certificates, sorted by path
+type DriftReport struct {
+	GeneratedAt  time.Time             `json:"generatedAt"`
+	Certificates []DeployedCertificate `json:"certificates"`
+	Errors       []FileError           `json:"errors,omitempty"`
+}
+
+// Count returns the number of certificates with the given status
+func (r *DriftReport) Count(status DriftStatus) int {
+	count := 0
+	for _, certificate := range r.Certificates {
+		if certificate.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Drifted returns the certificates that are not current
+func (r *DriftReport) Drifted() []DeployedCertificate {
+	var drifted []DeployedCertificate
+	for _, certificate := range r.Certificates {
+		if certificate.Status != DriftCurrent {
+			drifted = append(drifted, certificate)
+		}
+	}
+	return drifted
+}
+
+type ReconcileOptions struct {
+	// Passwords are tried in order to decrypt PKCS#12 files
+	Passwords []string
+}
+
+// Reconcile reads the PEM, DER and PKCS#12 certificates in the given files or directories and compares them to Horizon by thumbprint.
+// CA certificates, e.g. the chain of a PEM bundle, are ignored. A file that cannot be read or looked up is reported in Errors.
+func (c *Client) Reconcile(paths []string, options ReconcileOptions) (*DriftReport, error) {
+	return c.reconcile(paths, options, time.Now())
+}
+
+func (c *Client) reconcile(paths []string, options ReconcileOptions, now time.Time) (*DriftReport, error) {
+	report := DriftReport{GeneratedAt: now}
+	seen := make(map[string]bool)
+	for _, root := range paths {
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				report.Errors = append(report.Errors, FileError{Path: path, Err: err})
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			file, err := horizon.ReadCertificateFile(path, options.Passwords)
+			if err != nil {
+				report.Errors = append(report.Errors, FileError{Path: path, Err: err})
+				return nil
+			}
+			for _, certificate := range file.Certificates {
+				key := path + "\x00" + horizon.Thumbprint(certificate)
+				if certificate.IsCA || seen[key] {
+					continue
+				}
+				seen[key] = true
+				deployed, err := c.classify(path, certificate, now)
+				if err != nil {
+					report.Errors = append(report.Errors, FileError{Path: path, Err: err})
+					continue
+				}
+				report.Certificates = append(report.Certificates, *deployed)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.SliceStable(report.Certificates, func(i, j int) bool { return report.Certificates[i].Path < report.Certificates[j].Path })
+	return &report, nil
+}
+
+func (c *Client) classify(path string, certificate *x509.Certificate, now time.Time) (*DeployedCertificate, error) {
+	deployed := DeployedCertificate{
+		Path:       path,
+		Dn:         certificate.Subject.String(),
+		Thumbprint: horizon.Thumbprint(certificate),
+		NotAfter:   certificate.NotAfter,
+	}
+	known, err := c.GetByThumbprint(deployed.Thumbprint)
+	if errors.Is(err, CertificateNotFoundError) {
+		deployed.Status = DriftUnknown
+		return &deployed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	deployed.Horizon = known
+	if renewedId, ok := known.GetMetadata(horizon.MetadataRenewedCertificateId); ok && renewedId != "" {
+		deployed.RenewedCertificateId = renewedId
+	}
+	switch {
+	case known.Revoked:
+		deployed.Status = DriftRevoked
+	case deployed.RenewedCertificateId != "":
+		deployed.Status = DriftRenewedElsewhere
+	case now.After(certificate.NotAfter):
+		deployed.Status = DriftExpired
+	default:
+		deployed.Status = DriftCurrent
+	}
+	return &deployed, nil
+}
+
+// Write renders the report. CSV and text have a row per certificate followed by a row per error, HTML is not supported.
+func (r *DriftReport) Write(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case ReportCSV:
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"path", "status", "dn", "thumbprint", "notAfter", "renewedCertificateId", "error"})
+		for _, c := range r.Certificates {
+			_ = writer.Write([]string{c.Path, string(c.Status), c.Dn, c.Thumbprint, c.NotAfter.UTC().Format(time.RFC3339), c.RenewedCertificateId, ""})
+		}
+		for _, e := range r.Errors {
+			_ = writer.Write([]string{e.Path, "", "", "", "", "", e.Err.Error()})
+		}
+		writer.Flush()
+		return writer.Error()
+	case ReportText:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(writer, "PATH\tSTATUS\tDN\tNOT AFTER\n")
+		for _, c := range r.Certificates {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", c.Path, c.Status, c.Dn, c.NotAfter.Format("2006-01-02 15:04 MST"))
+		}
+		for _, e := range r.Errors {
+			fmt.Fprintf(writer, "%s\terror\t%s\t\n", e.Path, e.Err.Error())
+		}
+		return writer.Flush()
+	}
+	return fmt.Errorf("unsupported report format '%s'", format)
+}
@@ -0,0 +1,118 @@
+package certificates
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestReconcile(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	issue := func(name string, notAfter time.Time, isCA bool) (*x509.Certificate, []byte) {
+		template := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: name}, NotBefore: time.Now().Add(-time.Hour), NotAfter: notAfter, IsCA: isCA, BasicConstraintsValid: true}
+		der, _ := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+		certificate, _ := x509.ParseCertificate(der)
+		return certificate, der
+	}
+	dir := t.TempDir()
+	known := make(map[string]horizon.Certificate)
+	write := func(name string, certificate horizon.Certificate, notAfter time.Time, asDer bool) {
+		parsed, der := issue(name, notAfter, false)
+		if certificate.Id != "" {
+			known[`thumbprint equals "`+horizon.Thumbprint(parsed)+`"`] = certificate
+		}
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+		if asDer {
+			data = der
+		}
+		_ = os.WriteFile(filepath.Join(dir, name), data, 0600)
+	}
+	later := time.Now().Add(24 * time.Hour)
+	write("current.der", horizon.Certificate{Id: "current"}, later, true)
+	write("revoked.pem", horizon.Certificate{Id: "revoked", Revoked: true}, later, false)
+	write("renewed.pem", horizon.Certificate{Id: "renewed", Metadata: []horizon.Metadata{{Key: string(horizon.MetadataRenewedCertificateId), Value: "next"}}}, later, false)
+	write("expired.pem", horizon.Certificate{Id: "expired"}, time.Now().Add(-time.Minute), false)
+	write("unknown.pem", horizon.Certificate{}, later, false)
+	// The CA certificate of a bundle is ignored
+	bundle, _ := os.ReadFile(filepath.Join(dir, "unknown.pem"))
+	_, caDer := issue("ca", later, true)
+	_ = os.WriteFile(filepath.Join(dir, "unknown.pem"), append(bundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer})...), 0600)
+	_ = os.WriteFile(filepath.Join(dir, "garbage.txt"), []byte("not a certificate"), 0600)
+	// A PKCS#12 file, decrypted with the second password
+	p12Certificate, _ := issue("p12", later, false)
+	known[`thumbprint equals "`+horizon.Thumbprint(p12Certificate)+`"`] = horizon.Certificate{Id: "p12"}
+	p12, _ := pkcs12.Modern.Encode(key, p12Certificate, nil, "secret")
+	_ = os.WriteFile(filepath.Join(dir, "current.p12"), p12, 0600)
+
+	byId := make(map[string]horizon.Certificate)
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			_ = json.NewEncoder(w).Encode(horizon.CertificateResponse{Certificate: byId[strings.TrimPrefix(r.URL.Path, "/api/v1/certificates/")]})
+			return
+		}
+		var query horizon.CertificateSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		var results []horizon.CertificateSearchResult
+		if certificate, found := known[query.Query]; found {
+			byId[certificate.Id] = certificate
+			results = append(results, horizon.CertificateSearchResult{Id: certificate.Id})
+		}
+		_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: results})
+	})
+
+	report, err := client.Reconcile([]string{dir}, ReconcileOptions{Passwords: []string{"wrong", "secret"}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	statuses := make(map[string]DriftStatus)
+	for _, certificate := range report.Certificates {
+		statuses[filepath.Base(certificate.Path)] = certificate.Status
+	}
+	expected := map[string]DriftStatus{
+		"current.der": DriftCurrent,
+		"current.p12": DriftCurrent,
+		"revoked.pem": DriftRevoked,
+		"renewed.pem": DriftRenewedElsewhere,
+		"expired.pem": DriftExpired,
+		"unknown.pem": DriftUnknown,
+	}
+	if len(statuses) != len(expected) || len(report.Certificates) != len(expected) {
+		t.Fatalf("unexpected certificates %v", statuses)
+	}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("%s should be %s, got %s", name, status, statuses[name])
+		}
+	}
+	if len(report.Errors) != 1 || filepath.Base(report.Errors[0].Path) != "garbage.txt" {
+		t.Errorf("unexpected errors %v", report.Errors)
+	}
+	if len(report.Drifted()) != 4 || report.Count(DriftCurrent) != 2 {
+		t.Error("only two certificates should be current")
+	}
+
+	var buffer bytes.Buffer
+	if err := report.Write(&buffer, ReportCSV); err != nil || strings.Count(buffer.String(), "\n") != 8 || !strings.Contains(buffer.String(), ",next,") {
+		t.Errorf("unexpected CSV report %s (%v)", buffer.String(), err)
+	}
+	buffer.Reset()
+	if err := report.Write(&buffer, ReportJSON); err != nil || !strings.Contains(buffer.String(), `"status": "renewed-elsewhere"`) {
+		t.Errorf("unexpected JSON report %s (%v)", buffer.String(), err)
+	}
+}
@@ -0,0 +1,194 @@
+package certificates
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Expiry report
+
+type GroupBy string
+
+const (
+	GroupByOwner   GroupBy = "owner"
+	GroupByTeam    GroupBy = "team"
+	GroupByProfile GroupBy = "profile"
+	GroupByModule  GroupBy = "module"
+)
+
+type ReportFormat string
+
+const (
+	ReportJSON ReportFormat = "json"
+	ReportCSV  ReportFormat = "csv"
+	ReportHTML ReportFormat = "html"
+	ReportText ReportFormat = "text"
+)
+
+// NoGroup is the key of the group of certificates without owner, team, etc.
+const NoGroup = "(none)"
+
+type ExpiryReportOptions struct {
+	// Window in which certificates expire, starting now
+	Window  time.Duration
+	GroupBy GroupBy
+	// Query is an optional HPQL filter restricting the certificates reported
+	Query string
+}
+
+type ExpiringCertificate struct {
+	Id       string    `json:"id"`
+	Dn       string    `json:"dn"`
+	Serial   string    `json:"serial"`
+	Module   string    `json:"module"`
+	Profile  string    `json:"profile,omitempty"`
+	Owner    string    `json:"owner,omitempty"`
+	Team     string    `json:"team,omitempty"`
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// ExpiryGroup lists the expiring certificates sharing an owner, team, profile or module, from the soonest to expire
+type ExpiryGroup struct {
+	Key           string                `json:"key"`
+	Count         int                   `json:"count"`
+	SoonestExpiry time.Time             `json:"soonestExpiry"`
+	Certificates  []ExpiringCertificate `json:"certificates"`
+}
+
+// ExpiryReport groups the expiring certificates, the groups being sorted by soonest expiry
+type ExpiryReport struct {
+	GeneratedAt time.Time     `json:"generatedAt"`
+	Until       time.Time     `json:"until"`
+	GroupBy     GroupBy       `json:"groupBy"`
+	Total       int           `json:"total"`
+	Groups      []ExpiryGroup `json:"groups"`
+}
+
+// ExpiryReport searches the valid certificates expiring within the window and groups them
+func (c *Client) ExpiryReport(options ExpiryReportOptions) (*ExpiryReport, error) {
+	now := time.Now()
+	until := now.Add(options.Window)
+	query := fmt.Sprintf("status is valid and notAfter before %s", strconv.Quote(until.UTC().Format(time.RFC3339)))
+	if options.Query != "" {
+		query = "(" + options.Query + ") and " + query
+	}
+	var certificates []horizon.CertificateSearchResult
+	err := c.scan(horizon.CertificateSearchQuery{
+		Query:  query,
+		Fields: []string{"_id", "dn", "serial", "module", "profile", "owner", "team", "notAfter"},
+	}, func(result *horizon.CertificateSearchResult) {
+		certificates = append(certificates, *result)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buildExpiryReport(certificates, options.GroupBy, now, until), nil
+}
+
+func buildExpiryReport(certificates []horizon.CertificateSearchResult, groupBy GroupBy, now time.Time, until time.Time) *ExpiryReport {
+	report := ExpiryReport{GeneratedAt: now, Until: until, GroupBy: groupBy}
+	groups := make(map[string]*ExpiryGroup)
+	for _, result := range certificates {
+		certificate := ExpiringCertificate{
+			Id:       result.Id,
+			Dn:       result.Dn,
+			Serial:   result.Serial,
+			Module:   result.Module,
+			Profile:  result.Profile,
+			Owner:    result.Owner,
+			Team:     result.Team,
+			NotAfter: time.UnixMilli(int64(result.NotAfter)),
+		}
+		var key string
+		switch groupBy {
+		case GroupByOwner:
+			key = certificate.Owner
+		case GroupByTeam:
+			key = certificate.Team
+		case GroupByProfile:
+			key = certificate.Profile
+		case GroupByModule:
+			key = certificate.Module
+		}
+		if key == "" {
+			key = NoGroup
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &ExpiryGroup{Key: key}
+			groups[key] = group
+		}
+		group.Certificates = append(group.Certificates, certificate)
+		group.Count++
+		report.Total++
+	}
+	for _, group := range groups {
+		sort.SliceStable(group.Certificates, func(i, j int) bool {
+			return group.Certificates[i].NotAfter.Before(group.Certificates[j].NotAfter)
+		})
+		group.SoonestExpiry = group.Certificates[0].NotAfter
+		report.Groups = append(report.Groups, *group)
+	}
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].SoonestExpiry.Equal(report.Groups[j].SoonestExpiry) {
+			return report.Groups[i].Key < report.Groups[j].Key
+		}
+		return report.Groups[i].SoonestExpiry.Before(report.Groups[j].SoonestExpiry)
+	})
+	return &report
+}
+
+var expiryReportHTML = template.Must(template.New("report").Parse(`<table>
+<thead><tr><th>{{.GroupBy}}</th><th>Certificates</th><th>Soonest expiry</th></tr></thead>
+<tbody>
+{{- range .Groups}}
+<tr><td>{{.Key}}</td><td>{{.Count}}</td><td>{{.SoonestExpiry.Format "2006-01-02 15:04 MST"}}</td></tr>
+{{- range .Certificates}}
+<tr><td></td><td>{{.Dn}}</td><td>{{.NotAfter.Format "2006-01-02 15:04 MST"}}</td></tr>
+{{- end}}
+{{- end}}
+</tbody>
+</table>
+`))
+
+// Write renders the report. CSV has a row per certificate, HTML and text a row per group followed by its certificates.
+func (r *ExpiryReport) Write(w io.Writer, format ReportFormat) error {
+	switch format {
+	case ReportJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case ReportCSV:
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{string(r.GroupBy), "id", "dn", "serial", "module", "profile", "owner", "team", "notAfter"})
+		for _, group := range r.Groups {
+			for _, c := range group.Certificates {
+				_ = writer.Write([]string{group.Key, c.Id, c.Dn, c.Serial, c.Module, c.Profile, c.Owner, c.Team, c.NotAfter.UTC().Format(time.RFC3339)})
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case ReportHTML:
+		return expiryReportHTML.Execute(w, r)
+	case ReportText:
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintf(writer, "%s\tCERTIFICATES\tSOONEST EXPIRY\n", r.GroupBy)
+		for _, group := range r.Groups {
+			fmt.Fprintf(writer, "%s\t%d\t%s\n", group.Key, group.Count, group.SoonestExpiry.Format("2006-01-02 15:04 MST"))
+			for _, c := range group.Certificates {
+				fmt.Fprintf(writer, "\t%s\t%s\n", c.Dn, c.NotAfter.Format("2006-01-02 15:04 MST"))
+			}
+		}
+		return writer.Flush()
+	}
+	return fmt.Errorf("unsupported report format '%s'", format)
+}
@@ -0,0 +1,46 @@
+package certificates
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestExpiryReport(t *testing.T) {
+	now := time.UnixMilli(0)
+	certificates := []horizon.CertificateSearchResult{
+		{Id: "a", Dn: "CN=a", Owner: "john", NotAfter: 3000},
+		{Id: "b", Dn: "CN=b", Owner: "jane", NotAfter: 2000},
+		{Id: "c", Dn: "CN=c", Owner: "john", NotAfter: 1000},
+		{Id: "d", Dn: "CN=d", NotAfter: 4000},
+	}
+	report := buildExpiryReport(certificates, GroupByOwner, now, now.Add(time.Hour))
+	if report.Total != 4 || len(report.Groups) != 3 {
+		t.Fatalf("expected 3 groups of 4 certificates, got %d groups", len(report.Groups))
+	}
+	john := report.Groups[0]
+	if john.Key != "john" || john.Count != 2 || john.Certificates[0].Id != "c" || !john.SoonestExpiry.Equal(time.UnixMilli(1000)) {
+		t.Errorf("unexpected first group %+v", john)
+	}
+	if report.Groups[2].Key != NoGroup {
+		t.Error("certificates without owner should be grouped together")
+	}
+
+	for _, format := range []ReportFormat{ReportJSON, ReportCSV, ReportHTML, ReportText} {
+		var out bytes.Buffer
+		if err := report.Write(&out, format); err != nil {
+			t.Fatal(err.Error())
+		}
+		if !strings.Contains(out.String(), "CN=c") || !strings.Contains(out.String(), "jane") {
+			t.Errorf("%s report is missing certificates:\n%s", format, out.String())
+		}
+	}
+	var csv bytes.Buffer
+	_ = report.Write(&csv, ReportCSV)
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[1], "john,c,") {
+		t.Errorf("unexpected CSV report:\n%s", csv.String())
+	}
+}
@@ -0,0 +1,103 @@
+package certificates
+
+import (
+	"encoding/json"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Triggers and third parties
+
+// FailingTrigger is a trigger in failure on a certificate
+type FailingTrigger struct {
+	CertificateId string
+	Dn            string
+	Trigger       horizon.TriggerResult
+}
+
+// ThirdPartyDrift lists the third party connectors that do not hold the current version of a certificate
+type ThirdPartyDrift struct {
+	CertificateId string
+	Dn            string
+	Thumbprint    string
+	Connectors    []horizon.ThirdPartyItem
+}
+
+type retryTriggerRequest struct {
+	Name  string `json:"name"`
+	Event string `json:"event,omitempty"`
+}
+
+// scan pages through the certificates matching the query
+func (c *Client) scan(query horizon.CertificateSearchQuery, fn func(result *horizon.CertificateSearchResult)) error {
+	return scanAs(c, query, fn)
+}
+
+// scanAs pages through the certificates matching the query decoded as T, e.g. as raw JSON members to read any field
+func scanAs[T any](c *Client, query horizon.CertificateSearchQuery, fn func(result *T)) error {
+	for page := 1; ; page++ {
+		query.PageIndex = page
+		jsonData, _ := json.Marshal(query)
+		response, err := c.http.Post("/api/v1/certificates/search", jsonData)
+		if err != nil {
+			return err
+		}
+		var results horizon.SearchResults[T]
+		if err := response.Json().Decode(&results); err != nil {
+			return err
+		}
+		for i := range results.Results {
+			fn(&results.Results[i])
+		}
+		if !results.HasMore || len(results.Results) == 0 {
+			return nil
+		}
+	}
+}
+
+// FailingTriggers lists the triggers in failure on the certificates matching the HPQL query
+func (c *Client) FailingTriggers(query string) ([]FailingTrigger, error) {
+	var failing []FailingTrigger
+	err := c.scan(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id", "dn", "triggerResults"}}, func(result *horizon.CertificateSearchResult) {
+		for _, trigger := range result.FailedTriggers() {
+			failing = append(failing, FailingTrigger{CertificateId: result.Id, Dn: result.Dn, Trigger: trigger})
+		}
+	})
+	return failing, err
+}
+
+// RetryTriggers runs again the retryable triggers in failure on a certificate and returns their new results
+func (c *Client) RetryTriggers(certificateId string) ([]horizon.TriggerResult, error) {
+	certificate, err := c.Get(certificateId)
+	if err != nil {
+		return nil, err
+	}
+	var results []horizon.TriggerResult
+	for _, trigger := range certificate.FailedTriggers() {
+		if !trigger.Retryable {
+			continue
+		}
+		jsonData, _ := json.Marshal(retryTriggerRequest{Name: trigger.Name, Event: trigger.Event})
+		response, err := c.http.Post("/api/v1/certificates/"+certificateId+"/triggers/retry", jsonData)
+		if err != nil {
+			return results, err
+		}
+		var result horizon.TriggerResult
+		if err := response.Json().Decode(&result); err != nil {
+			return results, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ThirdPartyDrifts lists the certificates matching the HPQL query that are out of sync on at least one third party connector
+func (c *Client) ThirdPartyDrifts(query string) ([]ThirdPartyDrift, error) {
+	var drifts []ThirdPartyDrift
+	err := c.scan(horizon.CertificateSearchQuery{Query: query, Fields: []string{"_id", "dn", "thumbprint", "thirdPartyData"}}, func(result *horizon.CertificateSearchResult) {
+		if connectors := result.OutOfSyncThirdParties(); len(connectors) > 0 {
+			drifts = append(drifts, ThirdPartyDrift{CertificateId: result.Id, Dn: result.Dn, Thumbprint: result.Thumbprint, Connectors: connectors})
+		}
+	})
+	return drifts, err
+}
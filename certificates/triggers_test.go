@@ -0,0 +1,48 @@
+package certificates
+
+import (
+	"encoding/json"
+	gohttp "net/http"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestTriggers(t *testing.T) {
+	triggers := []horizon.TriggerResult{
+		{Name: "email", Status: horizon.TriggerResultStatusFailure},
+		{Name: "f5", Event: "enroll", Status: horizon.TriggerResultStatusFailure, Retryable: true},
+		{Name: "aws", Status: horizon.TriggerResultStatusSuccess},
+	}
+	var retried []string
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET":
+			_ = json.NewEncoder(w).Encode(horizon.CertificateResponse{Certificate: horizon.Certificate{Id: "a", TriggerResults: triggers}})
+		case r.URL.Path == "/api/v1/certificates/search":
+			_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: []horizon.CertificateSearchResult{
+				{Id: "a", Thumbprint: "new", TriggerResults: triggers, ThirdPartyData: []horizon.ThirdPartyItem{{Connector: "f5", Fingerprint: "old"}}},
+				{Id: "b", Thumbprint: "new", ThirdPartyData: []horizon.ThirdPartyItem{{Connector: "f5", Fingerprint: "new"}}},
+			}})
+		default:
+			var body retryTriggerRequest
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			retried = append(retried, r.URL.Path+" "+body.Name+" "+body.Event)
+			_ = json.NewEncoder(w).Encode(horizon.TriggerResult{Name: body.Name, Status: horizon.TriggerResultStatusSuccess})
+		}
+	})
+
+	failing, err := client.FailingTriggers("status is valid")
+	if err != nil || len(failing) != 2 || failing[0].CertificateId != "a" {
+		t.Errorf("expected two failing triggers, got %v (%v)", failing, err)
+	}
+	results, err := client.RetryTriggers("a")
+	if err != nil || len(results) != 1 || len(retried) != 1 || retried[0] != "/api/v1/certificates/a/triggers/retry f5 enroll" {
+		t.Errorf("only the retryable trigger should be retried, got %v (%v)", retried, err)
+	}
+	drifts, err := client.ThirdPartyDrifts("")
+	if err != nil || len(drifts) != 1 || drifts[0].CertificateId != "a" || drifts[0].Connectors[0].Connector != "f5" {
+		t.Errorf("unexpected drifts %v (%v)", drifts, err)
+	}
+}
@@ -0,0 +1,75 @@
+package horizon
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestCertificateAccessors(t *testing.T) {
+	_, cert := selfSigned(t, "example.org")
+	certificatePem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+	data, _ := json.Marshal(map[string]interface{}{
+		"certificate": certificatePem,
+		"notBefore":   1000,
+		"notAfter":    5000,
+		"subjectAlternateNames": []map[string]string{
+			{"sanType": "DNSNAME", "value": "example.org"},
+			{"sanType": "DNSNAME", "value": "www.example.org"},
+			{"sanType": "IPADDRESS", "value": "127.0.0.1"},
+		},
+	})
+	var certificate Certificate
+	if err := json.Unmarshal(data, &certificate); err != nil {
+		t.Fatal(err.Error())
+	}
+	parsed, err := certificate.X509()
+	if err != nil || parsed.Subject.CommonName != "example.org" {
+		t.Fatalf("certificate should be parsed, got %v", err)
+	}
+	if len(certificate.SubjectAlternateNames.OfType(SANDnsName)) != 2 {
+		t.Error("expected two DNS names")
+	}
+	if !certificate.GetNotAfter().Equal(time.UnixMilli(5000)) || !certificate.GetRevocationDate().IsZero() {
+		t.Error("unexpected dates")
+	}
+	if !certificate.IsExpired() || !certificate.ExpiresWithin(time.Hour) {
+		t.Error("certificate should be expired")
+	}
+	if unknown := (Certificate{}); unknown.IsExpired() || unknown.ExpiresWithin(time.Hour) {
+		t.Error("a certificate without an expiration date should not be expired")
+	}
+	if ratio := certificate.remainingLifetimeRatioAt(time.UnixMilli(2000)); math.Abs(ratio-0.75) > 1e-9 {
+		t.Errorf("expected 0.75 of the lifetime remaining, got %f", ratio)
+	}
+
+	certificate.Certificate = "invalid"
+	if _, err := certificate.X509(); err == nil {
+		t.Error("the current certificate should be parsed")
+	}
+}
+
+func TestThirdPartiesAndTriggers(t *testing.T) {
+	certificate := Certificate{
+		Thumbprint: "abcd",
+		ThirdPartyData: []ThirdPartyItem{
+			{Connector: "current", Fingerprint: "ABCD"},
+			{Connector: "stale", Fingerprint: "0000"},
+			{Connector: "removed", Fingerprint: "abcd", RemoveDate: 1000},
+			{Connector: "removed-stale", Fingerprint: "0000", RemoveDate: 1000},
+		},
+		TriggerResults: []TriggerResult{
+			{Name: "ok", Status: TriggerResultStatusSuccess},
+			{Name: "ko", Status: TriggerResultStatusFailure},
+		},
+	}
+	outOfSync := certificate.OutOfSyncThirdParties()
+	if len(outOfSync) != 1 || outOfSync[0].Connector != "stale" {
+		t.Errorf("unexpected out of sync connectors %v", outOfSync)
+	}
+	if failed := certificate.FailedTriggers(); len(failed) != 1 || failed[0].Name != "ko" {
+		t.Errorf("unexpected failed triggers %v", failed)
+	}
+}
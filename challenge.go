@@ -0,0 +1,60 @@
+package horizon
+
+import (
+	"errors"
+	"time"
+)
+
+// Challenges
+
+var DnRequiredError = errors.New("a DN is required as the profile is DN whitelisted")
+
+// Challenge is a SCEP or EST challenge password, to be used by a device to enroll a certificate.
+// Value is empty while the request is waiting for approval.
+type Challenge struct {
+	Module    Module
+	RequestId string
+	Status    Status
+	Profile   string
+	// Dn is the only DN the challenge can be used for, if the profile is DN whitelisted
+	Dn    string
+	Value string
+	// ExpiresAt is the date after which the challenge can no longer be used
+	ExpiresAt time.Time
+}
+
+// IsReady returns true if the challenge was issued and has not expired yet
+func (c *Challenge) IsReady() bool {
+	return c.Value != "" && (c.ExpiresAt.IsZero() || time.Now().Before(c.ExpiresAt))
+}
+
+func newChallenge(module Module, id string, status Status, profile string, dn string, secret *Secret, expirationDate int64) *Challenge {
+	challenge := Challenge{
+		Module:    module,
+		RequestId: id,
+		Status:    status,
+		Profile:   profile,
+		Dn:        dn,
+		ExpiresAt: millisToTime(expirationDate),
+	}
+	if secret != nil {
+		challenge.Value = secret.Value
+	}
+	return &challenge
+}
+
+func (r *ScepChallengeRequest) GetChallenge() *Challenge {
+	return newChallenge(Scep, r.Id, r.Status, r.Profile, r.Dn, r.Challenge, r.ExpirationDate)
+}
+
+func (r *ScepChallengeRenewRequest) GetChallenge() *Challenge {
+	return newChallenge(Scep, r.Id, r.Status, r.Profile, r.Dn, r.Challenge, r.ExpirationDate)
+}
+
+func (r *EstChallengeRequest) GetChallenge() *Challenge {
+	return newChallenge(Est, r.Id, r.Status, r.Profile, r.Dn, r.Challenge, r.ExpirationDate)
+}
+
+func (r *EstChallengeRenewRequest) GetChallenge() *Challenge {
+	return newChallenge(Est, r.Id, r.Status, r.Profile, r.Dn, r.Challenge, r.ExpirationDate)
+}
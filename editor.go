@@ -0,0 +1,271 @@
+package horizon
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Template edition
+
+var FieldNotFoundError = errors.New("field not found in template")
+var FieldNotEditableError = errors.New("field is not editable")
+var TooManyValuesError = errors.New("too many values for field")
+
+// TemplateEditor fills a template returned by Horizon while enforcing its editability constraints.
+// Its methods can be chained, the errors encountered along the way being returned by Err.
+type TemplateEditor struct {
+	subject      *[]IndexedDNElement
+	sans         *[]ListSANElement
+	owner        **OwnerElement
+	team         **TeamElement
+	contactEmail **ContactEmailElement
+	labels       *[]LabelElement
+	metadata     *[]MetadataElement
+	errs         []error
+}
+
+func (e *TemplateEditor) fail(err error, format string, args ...interface{}) *TemplateEditor {
+	e.errs = append(e.errs, fmt.Errorf("%w: %s", err, fmt.Sprintf(format, args...)))
+	return e
+}
+
+// Err returns the errors encountered while editing the template, or nil if every edition succeeded
+func (e *TemplateEditor) Err() error {
+	return errors.Join(e.errs...)
+}
+
+func newStringValue(value string) *String {
+	if value == "" {
+		return Delete
+	}
+	return &String{value}
+}
+
+// subjectElement returns the index-th (starting at 1) subject element of the given type
+func (e *TemplateEditor) subjectElement(dnType string, index int) *IndexedDNElement {
+	if e.subject == nil {
+		return nil
+	}
+	found := 0
+	for i := range *e.subject {
+		element := &(*e.subject)[i]
+		if strings.EqualFold(element.Type, dnType) {
+			found++
+			if found == index {
+				return element
+			}
+		}
+	}
+	return nil
+}
+
+// SetSubject sets the first subject element of the given type (e.g. "CN")
+func (e *TemplateEditor) SetSubject(dnType string, value string) *TemplateEditor {
+	return e.SetSubjectAt(dnType, 1, value)
+}
+
+// SetSubjectAt sets the index-th (starting at 1) subject element of the given type, e.g. ("OU", 2) for ou.2
+func (e *TemplateEditor) SetSubjectAt(dnType string, index int, value string) *TemplateEditor {
+	element := e.subjectElement(dnType, index)
+	if element == nil {
+		return e.fail(FieldNotFoundError, "subject element %s.%d", strings.ToLower(dnType), index)
+	}
+	if !element.Editable {
+		return e.fail(FieldNotEditableError, "subject element %s", element.Element)
+	}
+	element.Value = value
+	return e
+}
+
+// AddSubject sets the first empty subject element of the given type
+func (e *TemplateEditor) AddSubject(dnType string, value string) *TemplateEditor {
+	for index := 1; ; index++ {
+		element := e.subjectElement(dnType, index)
+		if element == nil {
+			return e.fail(FieldNotFoundError, "no empty subject element %s left", strings.ToLower(dnType))
+		}
+		if element.Value == "" && element.Editable {
+			element.Value = value
+			return e
+		}
+	}
+}
+
+func (e *TemplateEditor) sanElement(sanType string) (*ListSANElement, error) {
+	if e.sans != nil {
+		for i := range *e.sans {
+			if strings.EqualFold((*e.sans)[i].Type, sanType) {
+				element := &(*e.sans)[i]
+				if !element.Editable {
+					return nil, fmt.Errorf("%w: %s SANs", FieldNotEditableError, element.Type)
+				}
+				return element, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: %s SANs", FieldNotFoundError, sanType)
+}
+
+// AddSAN appends a value to the SANs of the given type (e.g. "DNSNAME"), within the maximum allowed by the template
+func (e *TemplateEditor) AddSAN(sanType string, value string) *TemplateEditor {
+	element, err := e.sanElement(sanType)
+	if err != nil {
+		e.errs = append(e.errs, err)
+		return e
+	}
+	if element.Max > 0 && len(element.Value) >= element.Max {
+		return e.fail(TooManyValuesError, "at most %d %s SAN(s) allowed", element.Max, element.Type)
+	}
+	element.Value = append(element.Value, value)
+	return e
+}
+
+// SetSANs replaces the SANs of the given type
+func (e *TemplateEditor) SetSANs(sanType string, values ...string) *TemplateEditor {
+	element, err := e.sanElement(sanType)
+	if err != nil {
+		e.errs = append(e.errs, err)
+		return e
+	}
+	if element.Max > 0 && len(values) > element.Max {
+		return e.fail(TooManyValuesError, "at most %d %s SAN(s) allowed", element.Max, element.Type)
+	}
+	element.Value = values
+	return e
+}
+
+// SetLabel sets the value of a label, an empty value deleting it
+func (e *TemplateEditor) SetLabel(label string, value string) *TemplateEditor {
+	if e.labels != nil {
+		for i := range *e.labels {
+			element := &(*e.labels)[i]
+			if element.Label == label {
+				if !element.Editable {
+					return e.fail(FieldNotEditableError, "label %s", label)
+				}
+				element.Value = newStringValue(value)
+				return e
+			}
+		}
+	}
+	return e.fail(FieldNotFoundError, "label %s", label)
+}
+
+// ClearLabel deletes the value of a label
+func (e *TemplateEditor) ClearLabel(label string) *TemplateEditor {
+	return e.SetLabel(label, "")
+}
+
+// SetMetadata sets the value of a metadata, an empty value deleting it
+func (e *TemplateEditor) SetMetadata(metadata MetadataType, value string) *TemplateEditor {
+	if e.metadata != nil {
+		for i := range *e.metadata {
+			element := &(*e.metadata)[i]
+			if element.Metadata == metadata {
+				if !element.Editable {
+					return e.fail(FieldNotEditableError, "metadata %s", metadata)
+				}
+				element.Value = newStringValue(value)
+				return e
+			}
+		}
+	}
+	return e.fail(FieldNotFoundError, "metadata %s", metadata)
+}
+
+// ClearMetadata deletes the value of a metadata
+func (e *TemplateEditor) ClearMetadata(metadata MetadataType) *TemplateEditor {
+	return e.SetMetadata(metadata, "")
+}
+
+// SetOwner sets the owner, an empty value deleting it
+func (e *TemplateEditor) SetOwner(value string) *TemplateEditor {
+	if e.owner == nil || *e.owner == nil {
+		return e.fail(FieldNotFoundError, "owner")
+	}
+	if !(*e.owner).Editable {
+		return e.fail(FieldNotEditableError, "owner")
+	}
+	(*e.owner).Value = newStringValue(value)
+	return e
+}
+
+// ClearOwner deletes the owner
+func (e *TemplateEditor) ClearOwner() *TemplateEditor {
+	return e.SetOwner("")
+}
+
+// SetTeam sets the team, an empty value deleting it
+func (e *TemplateEditor) SetTeam(value string) *TemplateEditor {
+	if e.team == nil || *e.team == nil {
+		return e.fail(FieldNotFoundError, "team")
+	}
+	if !(*e.team).Editable {
+		return e.fail(FieldNotEditableError, "team")
+	}
+	(*e.team).Value = newStringValue(value)
+	return e
+}
+
+// ClearTeam deletes the team
+func (e *TemplateEditor) ClearTeam() *TemplateEditor {
+	return e.SetTeam("")
+}
+
+// SetContactEmail sets the contact email, an empty value deleting it
+func (e *TemplateEditor) SetContactEmail(value string) *TemplateEditor {
+	if e.contactEmail == nil || *e.contactEmail == nil {
+		return e.fail(FieldNotFoundError, "contact email")
+	}
+	if !(*e.contactEmail).Editable {
+		return e.fail(FieldNotEditableError, "contact email")
+	}
+	(*e.contactEmail).Value = newStringValue(value)
+	return e
+}
+
+// ClearContactEmail deletes the contact email
+func (e *TemplateEditor) ClearContactEmail() *TemplateEditor {
+	return e.SetContactEmail("")
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *WebRAEnrollTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{subject: &t.Subject, sans: &t.Sans, owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *ScepChallengeTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{subject: &t.Subject, sans: &t.Sans, owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *EstChallengeTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{subject: &t.Subject, sans: &t.Sans, owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *AcmeEnrollTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{subject: &t.Subject, sans: &t.Sans, owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *AcmeExternalEnrollTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{subject: &t.Subject, sans: &t.Sans, owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *WebRAUpdateTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *WebRAMigrateTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
+
+// Editor returns a TemplateEditor modifying the template in place
+func (t *WebRAImportTemplate) Editor() *TemplateEditor {
+	return &TemplateEditor{owner: &t.Owner, team: &t.Team, contactEmail: &t.ContactEmail, labels: &t.Labels, metadata: &t.Metadata}
+}
@@ -0,0 +1,66 @@
+package horizon
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTemplateEditor(t *testing.T) {
+	template := WebRAEnrollTemplate{
+		Subject: []IndexedDNElement{
+			{Element: "cn.1", Type: "CN", Editable: true},
+			{Element: "ou.1", Type: "OU", Editable: true},
+			{Element: "ou.2", Type: "OU", Editable: true},
+			{Element: "o.1", Type: "O", Value: "Evertrust"},
+		},
+		Sans:     []ListSANElement{{Type: "DNSNAME", Editable: true, Max: 2}},
+		Owner:    &OwnerElement{Value: &String{"john"}, Editable: true},
+		Team:     &TeamElement{Value: &String{"dev"}},
+		Labels:   []LabelElement{{Label: "env", Editable: true}},
+		Metadata: []MetadataElement{{Metadata: MetadataAutomationPolicy, Editable: true}},
+	}
+	err := template.Editor().
+		SetSubject("cn", "example.org").
+		AddSubject("OU", "first").
+		AddSubject("OU", "second").
+		AddSAN("DNSNAME", "example.org").
+		SetLabel("env", "prod").
+		SetMetadata(MetadataAutomationPolicy, "policy").
+		ClearOwner().
+		Err()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if template.Subject[0].Value != "example.org" || template.Subject[1].Value != "first" || template.Subject[2].Value != "second" {
+		t.Errorf("unexpected subject %v", template.Subject)
+	}
+	if len(template.Sans[0].Value) != 1 || template.Labels[0].Value.String != "prod" || template.Metadata[0].Value.String != "policy" {
+		t.Error("SAN, label or metadata not set")
+	}
+	if template.Owner.Value != Delete || !template.Owner.Editable {
+		t.Error("owner should be deleted and remain editable")
+	}
+
+	err = template.Editor().
+		SetSubject("O", "Other").
+		SetTeam("ops").
+		SetLabel("unknown", "value").
+		AddSAN("DNSNAME", "a.example.org").
+		AddSAN("DNSNAME", "b.example.org").
+		Err()
+	if !errors.Is(err, FieldNotEditableError) || !errors.Is(err, FieldNotFoundError) {
+		t.Errorf("expected both not editable and not found errors, got %v", err)
+	}
+	if template.Subject[3].Value != "Evertrust" || template.Team.Value.String != "dev" {
+		t.Error("non editable fields should not be modified")
+	}
+	if !errors.Is(err, TooManyValuesError) {
+		t.Errorf("expected a too many values error, got %v", err)
+	}
+	if len(template.Sans[0].Value) != 2 {
+		t.Errorf("SANs should be capped to the template maximum, got %v", template.Sans[0].Value)
+	}
+	if err := template.Editor().SetSANs("DNSNAME", "a", "b", "c").Err(); !errors.Is(err, TooManyValuesError) || errors.Is(err, FieldNotEditableError) {
+		t.Errorf("expected only a too many values error, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package horizon
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Certificate export
+
+type CertificateFormat string
+
+const (
+	// PEM encodes the certificates as a PEM bundle
+	PEM CertificateFormat = "pem"
+	// DER encodes a single certificate
+	DER CertificateFormat = "der"
+	// PKCS7 encodes the certificates as a DER encoded certs-only PKCS#7 (.p7b)
+	PKCS7 CertificateFormat = "p7b"
+)
+
+var (
+	oidSignedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidData       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 1}
+)
+
+type pkcs7ContentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	// Content is the explicitly tagged [0] content, absent for the data content type of a degenerate SignedData
+	Content asn1.RawValue `asn1:"optional"`
+}
+
+type pkcs7SignedData struct {
+	Version          int
+	DigestAlgorithms asn1.RawValue
+	ContentInfo      pkcs7ContentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	SignerInfos      asn1.RawValue
+}
+
+// EncodeCertificates encodes the certificates in the given format, in the order they are given
+func EncodeCertificates(certificates []*x509.Certificate, format CertificateFormat) ([]byte, error) {
+	if len(certificates) == 0 {
+		return nil, errors.New("no certificate to encode")
+	}
+	switch format {
+	case PEM:
+		var data []byte
+		for _, certificate := range certificates {
+			data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})...)
+		}
+		return data, nil
+	case DER:
+		if len(certificates) > 1 {
+			return nil, errors.New("the DER format holds a single certificate")
+		}
+		return certificates[0].Raw, nil
+	case PKCS7:
+		return encodePkcs7(certificates)
+	}
+	return nil, fmt.Errorf("unsupported certificate format '%s'", format)
+}
+
+// encodePkcs7 builds a degenerate SignedData, with no signer, holding the certificates
+func encodePkcs7(certificates []*x509.Certificate) ([]byte, error) {
+	var raw []byte
+	for _, certificate := range certificates {
+		raw = append(raw, certificate.Raw...)
+	}
+	emptySet := asn1.RawValue{Class: asn1.ClassUniversal, Tag: asn1.TagSet, IsCompound: true}
+	signedData, err := asn1.Marshal(pkcs7SignedData{
+		Version:          1,
+		DigestAlgorithms: emptySet,
+		ContentInfo:      pkcs7ContentInfo{ContentType: oidData},
+		Certificates:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: raw},
+		SignerInfos:      emptySet,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asn1.Marshal(pkcs7ContentInfo{
+		ContentType: oidSignedData,
+		Content:     asn1.RawValue{Class: asn1.ClassContextSpecific, Tag: 0, IsCompound: true, Bytes: signedData},
+	})
+}
+
+// WriteFileAtomic writes data to a temporary file in the same directory and renames it to path,
+// so that readers such as web servers never see a partially written file
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,61 @@
+package horizon
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodeCertificates(t *testing.T) {
+	_, leaf := selfSigned(t, "leaf")
+	_, ca := selfSigned(t, "ca")
+	chain := []*x509.Certificate{leaf, ca}
+
+	data, err := EncodeCertificates(chain, PEM)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if parsed, err := ParseCertificatePem(string(data)); err != nil || parsed.Subject.CommonName != "leaf" {
+		t.Error("the PEM bundle should start with the leaf")
+	}
+	if _, err := EncodeCertificates(chain, DER); err == nil {
+		t.Error("DER cannot hold a chain")
+	}
+
+	data, err = EncodeCertificates(chain, PKCS7)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var contentInfo pkcs7ContentInfo
+	var signedData pkcs7SignedData
+	if _, err := asn1.Unmarshal(data, &contentInfo); err != nil || !contentInfo.ContentType.Equal(oidSignedData) {
+		t.Fatalf("invalid PKCS#7 content info: %v", err)
+	}
+	if _, err := asn1.Unmarshal(contentInfo.Content.Bytes, &signedData); err != nil {
+		t.Fatalf("invalid PKCS#7 signed data: %v", err)
+	}
+	certificates, err := x509.ParseCertificates(signedData.Certificates.Bytes)
+	if err != nil || len(certificates) != 2 || certificates[1].Subject.CommonName != "ca" {
+		t.Errorf("the PKCS#7 should hold the chain, got %v", err)
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cert.pem")
+	if err := os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := WriteFileAtomic(path, []byte("new"), 0600); err != nil {
+		t.Fatal(err.Error())
+	}
+	info, _ := os.Stat(path)
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" || info.Mode().Perm() != 0600 {
+		t.Errorf("unexpected file %s with mode %v", data, info.Mode())
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Error("temporary files should be removed")
+	}
+}
@@ -0,0 +1,106 @@
+package horizon
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Certificate files
+
+// CertificateFile is the content of a certificate or private key file
+type CertificateFile struct {
+	Certificates []*x509.Certificate
+	PrivateKeys  []crypto.PrivateKey
+}
+
+// ReadCertificateFile parses a PEM bundle, a DER certificate or private key, or a PKCS#12 file.
+// Passwords are tried in order to decrypt PKCS#12 files, the empty password being tried if none is given.
+// Nothing is returned from a file that cannot be fully parsed, e.g. a bundle holding an invalid certificate or an encrypted PEM key.
+func ReadCertificateFile(path string, passwords []string) (*CertificateFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".p12", ".pfx":
+		return readPkcs12File(data, passwords)
+	}
+	var file CertificateFile
+	if block, _ := pem.Decode(data); block != nil {
+		rest := data
+		for {
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				return &file, nil
+			}
+			switch {
+			case block.Type == "CERTIFICATE":
+				certificate, err := x509.ParseCertificate(block.Bytes)
+				if err != nil {
+					return nil, fmt.Errorf("invalid certificate: %s", err.Error())
+				}
+				file.Certificates = append(file.Certificates, certificate)
+			case strings.HasSuffix(block.Type, "PRIVATE KEY"):
+				if strings.Contains(block.Type, "ENCRYPTED") || block.Headers["Proc-Type"] != "" {
+					return nil, errors.New("encrypted PEM private keys are not supported")
+				}
+				key, err := parsePrivateKey(block.Bytes)
+				if err != nil {
+					return nil, err
+				}
+				file.PrivateKeys = append(file.PrivateKeys, key)
+			}
+		}
+	}
+	if certificates, err := x509.ParseCertificates(data); err == nil {
+		file.Certificates = certificates
+		return &file, nil
+	}
+	if key, err := parsePrivateKey(data); err == nil {
+		file.PrivateKeys = append(file.PrivateKeys, key)
+		return &file, nil
+	}
+	// Files without a known extension may still be PKCS#12
+	if pkcs12File, err := readPkcs12File(data, passwords); err == nil {
+		return pkcs12File, nil
+	}
+	return nil, errors.New("no certificate or private key found")
+}
+
+func readPkcs12File(data []byte, passwords []string) (*CertificateFile, error) {
+	if len(passwords) == 0 {
+		passwords = []string{""}
+	}
+	var err error
+	for _, password := range passwords {
+		var key interface{}
+		var certificate *x509.Certificate
+		var chain []*x509.Certificate
+		key, certificate, chain, err = pkcs12.DecodeChain(data, password)
+		if err == nil {
+			return &CertificateFile{Certificates: append([]*x509.Certificate{certificate}, chain...), PrivateKeys: []crypto.PrivateKey{key}}, nil
+		}
+	}
+	return nil, fmt.Errorf("could not decode PKCS#12: %s", err.Error())
+}
+
+func parsePrivateKey(der []byte) (crypto.PrivateKey, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("unsupported private key format")
+}
@@ -2,10 +2,11 @@ module github.com/evertrust/horizon-go
 
 go 1.20
 
-require gopkg.in/resty.v1 v1.12.0
-
 require (
-	github.com/golang-jwt/jwt v3.2.2+incompatible // indirect
-	golang.org/x/crypto v0.18.0 // indirect
-	golang.org/x/net v0.10.0 // indirect
+	github.com/golang-jwt/jwt v3.2.2+incompatible
+	golang.org/x/crypto v0.18.0
+	gopkg.in/resty.v1 v1.12.0
+	software.sslmate.com/src/go-pkcs12 v0.7.3
 )
+
+require golang.org/x/net v0.10.0 // indirect
@@ -49,6 +49,10 @@ func (c *Client) SetHttpClient(httpClient *gohttp.Client) *Client {
 		httpClient = gohttp.DefaultClient
 	}
 	c.client = *httpClient
+	if c.client.Transport == nil {
+		// Create the transport now so that concurrent requests do not race to initialize it
+		c.GetTransport()
+	}
 	return c
 }
 
@@ -289,11 +293,15 @@ func (c *Client) Patch(path string, body []byte) (response *HorizonResponse, err
 }
 
 func (c *Client) sendRequest(method, urlToRequest string, body []byte) (*gohttp.Response, error) {
-	// Setup url
-	urlToSend, err := url.JoinPath(c.baseUrl, urlToRequest)
+	// Setup url, keeping the query string out of JoinPath as it would escape it
+	pathToRequest, query, _ := strings.Cut(urlToRequest, "?")
+	urlToSend, err := url.JoinPath(c.baseUrl, pathToRequest)
 	if err != nil {
 		return nil, err
 	}
+	if query != "" {
+		urlToSend += "?" + query
+	}
 	var reader io.Reader
 	if body != nil {
 		reader = bytes.NewReader(body)
@@ -360,6 +368,9 @@ func (c *Client) unmarshal(r *gohttp.Response) (*HorizonResponse, error) {
 					HttpResponse: r,
 				}, &horizonMultiError
 			}
+			if horizonError.Status == 0 {
+				horizonError.Status = r.StatusCode
+			}
 			return &HorizonResponse{
 				HttpResponse: r,
 			}, &horizonError
@@ -368,6 +379,7 @@ func (c *Client) unmarshal(r *gohttp.Response) (*HorizonResponse, error) {
 				Code:    "Unknown",
 				Message: "Non-JSON error from Horizon",
 				Detail:  string(body),
+				Status:  r.StatusCode,
 			}
 		}
 	}
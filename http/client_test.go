@@ -0,0 +1,54 @@
+package http
+
+import (
+	gohttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler gohttp.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	endpoint, _ := url.Parse(server.URL)
+	client := Client{}
+	client.SetHttpClient(nil).SetBaseUrl(*endpoint)
+	return &client
+}
+
+func TestConcurrentRequests(t *testing.T) {
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	if client.client.Transport == nil {
+		t.Fatal("the transport should be created by SetHttpClient")
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Get("/api/v1/test"); err != nil {
+				t.Error(err.Error())
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestQueryStringIsNotEscaped(t *testing.T) {
+	var query string
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		query = r.URL.RawQuery
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{}`))
+	})
+	if _, err := client.Get("/api/v1/rfc5280/tc/cert?order=ltr"); err != nil {
+		t.Fatal(err.Error())
+	}
+	if query != "order=ltr" {
+		t.Errorf("expected the order query parameter, got %q", query)
+	}
+}
@@ -0,0 +1,138 @@
+package horizon
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Key types
+
+var ecdsaCurves = map[string]elliptic.Curve{
+	"secp256r1": elliptic.P256(),
+	"secp384r1": elliptic.P384(),
+	"secp521r1": elliptic.P521(),
+}
+
+// GenerateKey generates a private key of a Horizon key type, such as "rsa-2048", "ec-secp256r1" or "ed-Ed25519"
+func GenerateKey(keyType string) (crypto.Signer, error) {
+	family, size, _ := strings.Cut(strings.ToLower(keyType), "-")
+	switch family {
+	case "rsa":
+		bits, err := strconv.Atoi(size)
+		if err != nil || bits < 1024 {
+			return nil, fmt.Errorf("invalid RSA key size in key type '%s'", keyType)
+		}
+		return rsa.GenerateKey(rand.Reader, bits)
+	case "ec":
+		curve, ok := ecdsaCurves[size]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve in key type '%s'", keyType)
+		}
+		return ecdsa.GenerateKey(curve, rand.Reader)
+	case "ed":
+		if size != "ed25519" {
+			return nil, fmt.Errorf("unsupported curve in key type '%s'", keyType)
+		}
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		return key, err
+	}
+	return nil, fmt.Errorf("unsupported key type '%s'", keyType)
+}
+
+// KeyTypeOf returns the Horizon key type of a public key
+func KeyTypeOf(publicKey crypto.PublicKey) (string, error) {
+	switch key := publicKey.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("rsa-%d", key.N.BitLen()), nil
+	case *ecdsa.PublicKey:
+		for name, curve := range ecdsaCurves {
+			if key.Curve == curve {
+				return "ec-" + name, nil
+			}
+		}
+		return "", fmt.Errorf("unsupported curve %s", key.Curve.Params().Name)
+	case ed25519.PublicKey:
+		return "ed-Ed25519", nil
+	}
+	return "", fmt.Errorf("unsupported public key type %T", publicKey)
+}
+
+// keyStrength returns the family of a key type and its size, used to compare key types of the same family
+func keyStrength(keyType string) (string, int) {
+	family, size, _ := strings.Cut(strings.ToLower(keyType), "-")
+	switch family {
+	case "rsa":
+		bits, _ := strconv.Atoi(size)
+		return family, bits
+	case "ec":
+		bits, _ := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(size, "secp"), "r1"))
+		return family, bits
+	}
+	return family, 0
+}
+
+// UpgradeKeyType returns the key type to use in place of keyType given the capabilities of a template.
+// keyType is kept if it is authorized, otherwise the weakest authorized key type of the same family that is at least as strong is chosen,
+// falling back to the default key type of the template.
+func UpgradeKeyType(keyType string, capabilities *Capabilities) (string, error) {
+	if capabilities == nil || len(capabilities.AuthorizedKeyTypes) == 0 {
+		return keyType, nil
+	}
+	family, strength := keyStrength(keyType)
+	upgrade, upgradeStrength := "", 0
+	for _, authorized := range capabilities.AuthorizedKeyTypes {
+		if strings.EqualFold(authorized, keyType) {
+			return authorized, nil
+		}
+		authorizedFamily, authorizedStrength := keyStrength(authorized)
+		if authorizedFamily == family && authorizedStrength >= strength && (upgrade == "" || authorizedStrength < upgradeStrength) {
+			upgrade, upgradeStrength = authorized, authorizedStrength
+		}
+	}
+	if upgrade != "" {
+		return upgrade, nil
+	}
+	if capabilities.DefaultKeyType != "" {
+		return capabilities.DefaultKeyType, nil
+	}
+	return "", fmt.Errorf("key type '%s' is not authorized and no replacement was found in %s", keyType, strings.Join(capabilities.AuthorizedKeyTypes, ", "))
+}
+
+// ParseCertificatePem parses the first certificate of a PEM bundle
+func ParseCertificatePem(certificatePem string) (*x509.Certificate, error) {
+	rest := []byte(certificatePem)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, errors.New("no PEM encoded certificate found")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// Thumbprint returns the thumbprint of a certificate as computed by Horizon, the lowercase hex encoded SHA-256 of its DER encoding
+func Thumbprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// PublicKeyThumbprint returns the lowercase hex encoded SHA-256 of the DER encoded public key (SubjectPublicKeyInfo) of a certificate
+func PublicKeyThumbprint(certificate *x509.Certificate) string {
+	sum := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
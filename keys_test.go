@@ -0,0 +1,36 @@
+package horizon
+
+import "testing"
+
+func TestGenerateKey(t *testing.T) {
+	for _, keyType := range []string{"rsa-2048", "ec-secp256r1", "ec-secp384r1", "ed-Ed25519"} {
+		key, err := GenerateKey(keyType)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+		if actual, err := KeyTypeOf(key.Public()); err != nil || actual != keyType {
+			t.Errorf("expected key type %s, got %s (%v)", keyType, actual, err)
+		}
+	}
+	if _, err := GenerateKey("dsa-1024"); err == nil {
+		t.Error("unsupported key types should be rejected")
+	}
+}
+
+func TestUpgradeKeyType(t *testing.T) {
+	capabilities := &Capabilities{AuthorizedKeyTypes: []string{"rsa-4096", "rsa-3072", "ec-secp384r1"}, DefaultKeyType: "rsa-3072"}
+	cases := map[string]string{
+		"rsa-3072":     "rsa-3072",
+		"rsa-2048":     "rsa-3072",
+		"ec-secp256r1": "ec-secp384r1",
+		"ed-Ed25519":   "rsa-3072",
+	}
+	for keyType, expected := range cases {
+		if actual, err := UpgradeKeyType(keyType, capabilities); err != nil || actual != expected {
+			t.Errorf("expected %s to be upgraded to %s, got %s (%v)", keyType, expected, actual, err)
+		}
+	}
+	if _, err := UpgradeKeyType("ed-Ed25519", &Capabilities{AuthorizedKeyTypes: []string{"rsa-2048"}}); err == nil {
+		t.Error("an error is expected when no replacement exists")
+	}
+}
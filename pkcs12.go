@@ -0,0 +1,110 @@
+package horizon
+
+import (
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// PKCS#12
+
+var MissingPkcs12Error = errors.New("no PKCS#12 was returned by Horizon")
+var MissingPkcs12PasswordError = errors.New("no PKCS#12 password was returned by Horizon")
+
+// KeyMaterial is the content of a PKCS#12 generated by Horizon for centralized enrollments, renewals and recoveries
+type KeyMaterial struct {
+	PrivateKey  crypto.PrivateKey
+	Certificate *x509.Certificate
+	// Chain contains the CA certificates bundled with the leaf certificate, if any
+	Chain []*x509.Certificate
+}
+
+// DecodePkcs12 decodes a base64 encoded PKCS#12 as returned by Horizon.
+// Both legacy (RC2, 3DES) and AES (PBES2) encrypted PKCS#12 are supported, whatever the P12EncryptionType of the profile.
+func DecodePkcs12(pkcs12Base64 string, password string) (*KeyMaterial, error) {
+	der, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(pkcs12Base64), ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PKCS#12 encoding: %s", err.Error())
+	}
+	key, cert, chain, err := pkcs12.DecodeChain(der, password)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode PKCS#12: %s", err.Error())
+	}
+	return &KeyMaterial{PrivateKey: key, Certificate: cert, Chain: chain}, nil
+}
+
+func decodePkcs12Secrets(pkcs12 *Secret, password *Secret) (*KeyMaterial, error) {
+	if pkcs12 == nil || pkcs12.Value == "" {
+		return nil, MissingPkcs12Error
+	}
+	if password == nil || password.Value == "" {
+		return nil, MissingPkcs12PasswordError
+	}
+	return DecodePkcs12(pkcs12.Value, password.Value)
+}
+
+// TLSCertificate returns the key material as a tls.Certificate, with the chain appended after the leaf
+func (k *KeyMaterial) TLSCertificate() tls.Certificate {
+	certificate := tls.Certificate{
+		Certificate: [][]byte{k.Certificate.Raw},
+		PrivateKey:  k.PrivateKey,
+		Leaf:        k.Certificate,
+	}
+	for _, ca := range k.Chain {
+		certificate.Certificate = append(certificate.Certificate, ca.Raw)
+	}
+	return certificate
+}
+
+// PrivateKeyPem returns the private key as a PEM encoded PKCS#8
+func (k *KeyMaterial) PrivateKeyPem() (string, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(k.PrivateKey)
+	if err != nil {
+		return "", fmt.Errorf("could not marshal private key: %s", err.Error())
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})), nil
+}
+
+// CertificatePem returns the leaf certificate as PEM
+func (k *KeyMaterial) CertificatePem() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: k.Certificate.Raw}))
+}
+
+// ChainPem returns the CA certificates as a PEM bundle, in the order they were found in the PKCS#12
+func (k *KeyMaterial) ChainPem() string {
+	var builder strings.Builder
+	for _, ca := range k.Chain {
+		builder.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}))
+	}
+	return builder.String()
+}
+
+// FullChainPem returns the leaf certificate followed by its chain as a PEM bundle
+func (k *KeyMaterial) FullChainPem() string {
+	return k.CertificatePem() + k.ChainPem()
+}
+
+// KeyMaterial decodes the PKCS#12 of a centralized enrollment using the password returned by Horizon.
+// If the password was set on client side and is not sent back, use DecodePkcs12 instead.
+func (r *WebRAEnrollRequest) KeyMaterial() (*KeyMaterial, error) {
+	return decodePkcs12Secrets(r.Pkcs12, r.Password)
+}
+
+// KeyMaterial decodes the PKCS#12 of a centralized renewal using the password returned by Horizon.
+// If the password was set on client side and is not sent back, use DecodePkcs12 instead.
+func (r *WebRARenewRequest) KeyMaterial() (*KeyMaterial, error) {
+	return decodePkcs12Secrets(r.Pkcs12, r.Password)
+}
+
+// KeyMaterial decodes the PKCS#12 of a recovery using the password returned by Horizon.
+// If the password was set on client side and is not sent back, use DecodePkcs12 instead.
+func (r *WebRARecoverRequest) KeyMaterial() (*KeyMaterial, error) {
+	return decodePkcs12Secrets(r.Pkcs12, r.Password)
+}
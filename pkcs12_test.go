@@ -0,0 +1,85 @@
+package horizon
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"testing"
+	"time"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func selfSigned(t *testing.T, cn string) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	return key, cert
+}
+
+func TestKeyMaterial(t *testing.T) {
+	key, cert := selfSigned(t, "example.org")
+	_, ca := selfSigned(t, "Example CA")
+	encoders := map[string]*pkcs12.Encoder{
+		"rc2":    pkcs12.LegacyRC2,
+		"3des":   pkcs12.LegacyDES,
+		"aes256": pkcs12.Modern2023,
+	}
+	for name, encoder := range encoders {
+		t.Run(name, func(t *testing.T) {
+			der, err := encoder.Encode(key, cert, []*x509.Certificate{ca}, "secret")
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			request := WebRAEnrollRequest{
+				Pkcs12:   &Secret{Value: base64.StdEncoding.EncodeToString(der)},
+				Password: &Secret{Value: "secret"},
+			}
+			material, err := request.KeyMaterial()
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if !material.Certificate.Equal(cert) {
+				t.Error("leaf certificate mismatch")
+			}
+			if len(material.Chain) != 1 || !material.Chain[0].Equal(ca) {
+				t.Error("chain mismatch")
+			}
+			if !key.Equal(material.PrivateKey) {
+				t.Error("private key mismatch")
+			}
+			if len(material.TLSCertificate().Certificate) != 2 {
+				t.Error("tls certificate should contain the chain")
+			}
+			if _, err := material.PrivateKeyPem(); err != nil {
+				t.Error(err.Error())
+			}
+		})
+	}
+}
+
+func TestKeyMaterialMissingPassword(t *testing.T) {
+	request := WebRARecoverRequest{Pkcs12: &Secret{Value: "MA=="}}
+	if _, err := request.KeyMaterial(); err != MissingPkcs12PasswordError {
+		t.Errorf("expected MissingPkcs12PasswordError, got %v", err)
+	}
+}
@@ -0,0 +1,657 @@
+package horizon
+
+import "time"
+
+// Accessors shared by every request type, see the Request interface
+
+// millisToTime converts the epoch milliseconds used by Horizon to a time.Time, 0 being mapped to the zero time
+func millisToTime(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(millis)
+}
+
+// WebRAEnrollRequest
+
+func (r *WebRAEnrollRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRAEnrollRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRAEnrollRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRAEnrollRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRAEnrollRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRAEnrollRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRAEnrollRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRAEnrollRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRAEnrollRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRAEnrollRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRAEnrollRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// ScepChallengeRequest
+
+func (r *ScepChallengeRequest) GetId() string {
+	return r.Id
+}
+
+func (r *ScepChallengeRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *ScepChallengeRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *ScepChallengeRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *ScepChallengeRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *ScepChallengeRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *ScepChallengeRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *ScepChallengeRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *ScepChallengeRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *ScepChallengeRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *ScepChallengeRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// ScepChallengeRenewRequest
+
+func (r *ScepChallengeRenewRequest) GetId() string {
+	return r.Id
+}
+
+func (r *ScepChallengeRenewRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *ScepChallengeRenewRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *ScepChallengeRenewRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *ScepChallengeRenewRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *ScepChallengeRenewRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *ScepChallengeRenewRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *ScepChallengeRenewRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *ScepChallengeRenewRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *ScepChallengeRenewRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *ScepChallengeRenewRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// EstChallengeRequest
+
+func (r *EstChallengeRequest) GetId() string {
+	return r.Id
+}
+
+func (r *EstChallengeRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *EstChallengeRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *EstChallengeRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *EstChallengeRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *EstChallengeRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *EstChallengeRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *EstChallengeRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *EstChallengeRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *EstChallengeRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *EstChallengeRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// EstChallengeRenewRequest
+
+func (r *EstChallengeRenewRequest) GetId() string {
+	return r.Id
+}
+
+func (r *EstChallengeRenewRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *EstChallengeRenewRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *EstChallengeRenewRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *EstChallengeRenewRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *EstChallengeRenewRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *EstChallengeRenewRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *EstChallengeRenewRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *EstChallengeRenewRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *EstChallengeRenewRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *EstChallengeRenewRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRARenewRequest
+
+func (r *WebRARenewRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRARenewRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRARenewRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRARenewRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRARenewRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRARenewRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRARenewRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRARenewRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRARenewRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRARenewRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRARenewRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRARevokeRequest
+
+func (r *WebRARevokeRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRARevokeRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRARevokeRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRARevokeRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRARevokeRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRARevokeRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRARevokeRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRARevokeRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRARevokeRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRARevokeRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRARevokeRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRAUpdateRequest
+
+func (r *WebRAUpdateRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRAUpdateRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRAUpdateRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRAUpdateRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRAUpdateRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRAUpdateRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRAUpdateRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRAUpdateRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRAUpdateRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRAUpdateRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRAUpdateRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRAMigrateRequest
+
+func (r *WebRAMigrateRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRAMigrateRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRAMigrateRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRAMigrateRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRAMigrateRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRAMigrateRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRAMigrateRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRAMigrateRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRAMigrateRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRAMigrateRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRAMigrateRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRARecoverRequest
+
+func (r *WebRARecoverRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRARecoverRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRARecoverRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRARecoverRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRARecoverRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRARecoverRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRARecoverRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRARecoverRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRARecoverRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRARecoverRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRARecoverRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// WebRAImportRequest
+
+func (r *WebRAImportRequest) GetId() string {
+	return r.Id
+}
+
+func (r *WebRAImportRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *WebRAImportRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *WebRAImportRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *WebRAImportRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *WebRAImportRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *WebRAImportRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *WebRAImportRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *WebRAImportRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *WebRAImportRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *WebRAImportRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// AcmeEnrollRequest
+
+func (r *AcmeEnrollRequest) GetId() string {
+	return r.Id
+}
+
+func (r *AcmeEnrollRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *AcmeEnrollRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *AcmeEnrollRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *AcmeEnrollRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *AcmeEnrollRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *AcmeEnrollRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *AcmeEnrollRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *AcmeEnrollRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *AcmeEnrollRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *AcmeEnrollRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// AcmeExternalEnrollRequest
+
+func (r *AcmeExternalEnrollRequest) GetId() string {
+	return r.Id
+}
+
+func (r *AcmeExternalEnrollRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *AcmeExternalEnrollRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *AcmeExternalEnrollRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *AcmeExternalEnrollRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *AcmeExternalEnrollRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *AcmeExternalEnrollRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *AcmeExternalEnrollRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *AcmeExternalEnrollRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *AcmeExternalEnrollRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *AcmeExternalEnrollRequest) GetLabels() []Label {
+	return r.Labels
+}
+
+// AcmeExternalRenewRequest
+
+func (r *AcmeExternalRenewRequest) GetId() string {
+	return r.Id
+}
+
+func (r *AcmeExternalRenewRequest) GetModule() Module {
+	return r.Module
+}
+
+func (r *AcmeExternalRenewRequest) GetWorkflow() Workflow {
+	return r.Workflow
+}
+
+func (r *AcmeExternalRenewRequest) GetStatus() Status {
+	return r.Status
+}
+
+func (r *AcmeExternalRenewRequest) GetRegistrationDate() time.Time {
+	return millisToTime(r.RegistrationDate)
+}
+
+func (r *AcmeExternalRenewRequest) GetLastModificationDate() time.Time {
+	return millisToTime(r.LastModificationDate)
+}
+
+func (r *AcmeExternalRenewRequest) GetExpirationDate() time.Time {
+	return millisToTime(r.ExpirationDate)
+}
+
+func (r *AcmeExternalRenewRequest) GetContact() string {
+	return r.Contact
+}
+
+func (r *AcmeExternalRenewRequest) GetRequesterComment() string {
+	return r.RequesterComment
+}
+
+func (r *AcmeExternalRenewRequest) GetApproverComment() string {
+	return r.ApproverComment
+}
+
+func (r *AcmeExternalRenewRequest) GetLabels() []Label {
+	return r.Labels
+}
@@ -2,6 +2,7 @@ package horizon
 
 import (
 	"fmt"
+	"time"
 )
 
 // Requests
@@ -218,10 +219,24 @@ type Capabilities struct {
 	ShowP12PasswordOnRecover bool     `json:"showP12PasswordOnRecover,omitempty"`
 	ShowP12OnRecover         bool     `json:"showP12OnRecover,omitempty"`
 	P12PasswordMode          string   `json:"p12PasswordMode,omitempty"`
+	P12EncryptionType        string   `json:"p12EncryptionType,omitempty"`
 }
 
+// Request is implemented by every request type, whatever its module and workflow
 type Request interface {
 	EnsureType() error
+	GetId() string
+	GetModule() Module
+	GetWorkflow() Workflow
+	GetStatus() Status
+	GetRegistrationDate() time.Time
+	GetLastModificationDate() time.Time
+	GetExpirationDate() time.Time
+	GetContact() string
+	// GetRequesterComment returns the justification displayed to approvers, set through the RequesterComment of the request params
+	GetRequesterComment() string
+	GetApproverComment() string
+	GetLabels() []Label
 }
 
 type WebRAEnrollTemplateParams struct {
@@ -244,13 +259,18 @@ type WebRAEnrollTemplate struct {
 	Labels       []LabelElement       `json:"labels,omitempty"`
 	Metadata     []MetadataElement    `json:"metadata,omitempty"`
 	Capabilities *Capabilities        `json:"capabilities,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 type WebRAEnrollRequestParams struct {
 	Profile  string
 	Template *WebRAEnrollTemplate
 	// If the request allows password set on client side, give the password here
-	Password string
+	Password         string
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRAEnrollRequest struct {
@@ -280,6 +300,7 @@ type WebRAEnrollRequest struct {
 	HolderId             string               `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                  `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                  `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRAEnrollRequest) EnsureType() error {
@@ -307,6 +328,8 @@ type ScepChallengeTemplate struct {
 	Labels       []LabelElement       `json:"labels,omitempty"`
 	Metadata     []MetadataElement    `json:"metadata,omitempty"`
 	Capabilities *Capabilities        `json:"capabilities,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 func (t *ScepChallengeTemplate) IsDnWhitelist() bool {
@@ -315,9 +338,12 @@ func (t *ScepChallengeTemplate) IsDnWhitelist() bool {
 
 // Dn is mandatory if IsDnWhitelist is true for the template
 type ScepChallengeRequestParams struct {
-	Profile  string
-	Template *ScepChallengeTemplate
-	Dn       string
+	Profile          string
+	Template         *ScepChallengeTemplate
+	Dn               string
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type ScepChallengeRequest struct {
@@ -346,6 +372,7 @@ type ScepChallengeRequest struct {
 	HolderId             string                 `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                    `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                    `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *ScepChallengeRequest) EnsureType() error {
@@ -358,6 +385,59 @@ func (r *ScepChallengeRequest) EnsureType() error {
 	return nil
 }
 
+type ScepChallengeRenewTemplateParams struct {
+	CertificatePEM string
+	CertificateId  string
+}
+
+type ScepChallengeRenewRequestParams struct {
+	CertificatePEM   string
+	CertificateId    string
+	Template         *ScepChallengeTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
+}
+
+type ScepChallengeRenewRequest struct {
+	Id                   string                 `json:"_id,omitempty"`
+	Workflow             Workflow               `json:"workflow"`
+	Module               Module                 `json:"module,omitempty"`
+	Status               Status                 `json:"status,omitempty"`
+	Profile              string                 `json:"profile,omitempty"`
+	Dn                   string                 `json:"dn,omitempty"`
+	Requester            string                 `json:"requester,omitempty"`
+	Approver             string                 `json:"approver,omitempty"`
+	Contact              string                 `json:"contact,omitempty"`
+	RequesterComment     string                 `json:"requesterComment,omitempty"`
+	ApproverComment      string                 `json:"approverComment,omitempty"`
+	RegistrationDate     int64                  `json:"registrationDate"`
+	LastModificationDate int64                  `json:"lastModificationDate"`
+	ExpirationDate       int64                  `json:"expirationDate"`
+	RemoveAt             int64                  `json:"removeAt"`
+	Template             *ScepChallengeTemplate `json:"template,omitempty"`
+	CertificatePEM       string                 `json:"certificatePem,omitempty"`
+	CertificateId        string                 `json:"certificateId,omitempty"`
+	Certificate          *Certificate           `json:"certificate,omitempty"`
+	Challenge            *Secret                `json:"password,omitempty"`
+	Labels               []Label                `json:"labels,omitempty"`
+	Metadata             []Metadata             `json:"metadata,omitempty"`
+	HolderId             string                 `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                    `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                    `json:"profileHolderIdCount,omitempty"`
+	preservedFields
+}
+
+func (r *ScepChallengeRenewRequest) EnsureType() error {
+	if r.Module != Scep {
+		return invalidModuleError(r.Module, Scep)
+	}
+	if r.Workflow != Renew {
+		return invalidWorkflowError(r.Workflow, Renew)
+	}
+	return nil
+}
+
 type EstChallengeTemplateParams struct {
 	Profile string
 }
@@ -373,6 +453,8 @@ type EstChallengeTemplate struct {
 	Labels       []LabelElement       `json:"labels,omitempty"`
 	Metadata     []MetadataElement    `json:"metadata,omitempty"`
 	Capabilities *Capabilities        `json:"capabilities,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 func (t *EstChallengeTemplate) IsDnWhitelist() bool {
@@ -381,9 +463,12 @@ func (t *EstChallengeTemplate) IsDnWhitelist() bool {
 
 // Dn is mandatory if IsDnWhitelist is true for the template
 type EstChallengeRequestParams struct {
-	Profile  string
-	Template *EstChallengeTemplate
-	Dn       string
+	Profile          string
+	Template         *EstChallengeTemplate
+	Dn               string
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type EstChallengeRequest struct {
@@ -412,6 +497,7 @@ type EstChallengeRequest struct {
 	HolderId             string                `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                   `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                   `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *EstChallengeRequest) EnsureType() error {
@@ -424,6 +510,59 @@ func (r *EstChallengeRequest) EnsureType() error {
 	return nil
 }
 
+type EstChallengeRenewTemplateParams struct {
+	CertificatePEM string
+	CertificateId  string
+}
+
+type EstChallengeRenewRequestParams struct {
+	CertificatePEM   string
+	CertificateId    string
+	Template         *EstChallengeTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
+}
+
+type EstChallengeRenewRequest struct {
+	Id                   string                `json:"_id,omitempty"`
+	Workflow             Workflow              `json:"workflow"`
+	Module               Module                `json:"module,omitempty"`
+	Status               Status                `json:"status,omitempty"`
+	Profile              string                `json:"profile,omitempty"`
+	Dn                   string                `json:"dn,omitempty"`
+	Requester            string                `json:"requester,omitempty"`
+	Approver             string                `json:"approver,omitempty"`
+	Contact              string                `json:"contact,omitempty"`
+	RequesterComment     string                `json:"requesterComment,omitempty"`
+	ApproverComment      string                `json:"approverComment,omitempty"`
+	RegistrationDate     int64                 `json:"registrationDate"`
+	LastModificationDate int64                 `json:"lastModificationDate"`
+	ExpirationDate       int64                 `json:"expirationDate"`
+	RemoveAt             int64                 `json:"removeAt"`
+	Template             *EstChallengeTemplate `json:"template,omitempty"`
+	CertificatePEM       string                `json:"certificatePem,omitempty"`
+	CertificateId        string                `json:"certificateId,omitempty"`
+	Certificate          *Certificate          `json:"certificate,omitempty"`
+	Challenge            *Secret               `json:"password,omitempty"`
+	Labels               []Label               `json:"labels,omitempty"`
+	Metadata             []Metadata            `json:"metadata,omitempty"`
+	HolderId             string                `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                   `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                   `json:"profileHolderIdCount,omitempty"`
+	preservedFields
+}
+
+func (r *EstChallengeRenewRequest) EnsureType() error {
+	if r.Module != Est {
+		return invalidModuleError(r.Module, Est)
+	}
+	if r.Workflow != Renew {
+		return invalidWorkflowError(r.Workflow, Renew)
+	}
+	return nil
+}
+
 type WebRARenewTemplateParams struct {
 	CertificatePEM string
 	CertificateId  string
@@ -434,14 +573,18 @@ type WebRARenewTemplate struct {
 	KeyType      string        `json:"keyType,omitempty"`
 	Csr          string        `json:"csr,omitempty"`
 	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	preservedFields
 }
 
 type WebRARenewRequestParams struct {
 	Template *WebRARenewTemplate
 	// If the request allows password set on client side, give the password here
-	Password       string
-	CertToRenewId  string
-	CertToRenewPem string
+	Password         string
+	CertToRenewId    string
+	CertToRenewPem   string
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRARenewRequest struct {
@@ -471,6 +614,7 @@ type WebRARenewRequest struct {
 	HolderId             string              `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                 `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                 `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRARenewRequest) EnsureType() error {
@@ -483,14 +627,23 @@ func (r *WebRARenewRequest) EnsureType() error {
 	return nil
 }
 
+type WebRARevokeTemplateParams struct {
+	CertificatePEM string
+	CertificateId  string
+}
+
 type WebRARevokeTemplate struct {
 	RevocationReason RevocationReason `json:"revocationReason,omitempty"`
+	preservedFields
 }
 
 type WebRARevokeRequestParams struct {
 	CertificateId    string
 	CertificatePEM   string
 	RevocationReason RevocationReason
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRARevokeRequest struct {
@@ -519,6 +672,7 @@ type WebRARevokeRequest struct {
 	HolderId             string               `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                  `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                  `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRARevokeRequest) EnsureType() error {
@@ -539,12 +693,17 @@ type WebRAUpdateTemplate struct {
 	ContactEmail *ContactEmailElement `json:"contactEmail,omitempty"`
 	Labels       []LabelElement       `json:"labels,omitempty"`
 	Metadata     []MetadataElement    `json:"metadata,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 type WebRAUpdateRequestParams struct {
-	CertificatePEM string
-	CertificateId  string
-	Template       *WebRAUpdateTemplate
+	CertificatePEM   string
+	CertificateId    string
+	Template         *WebRAUpdateTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRAUpdateRequest struct {
@@ -573,6 +732,7 @@ type WebRAUpdateRequest struct {
 	HolderId             string               `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                  `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                  `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRAUpdateRequest) EnsureType() error {
@@ -595,13 +755,18 @@ type WebRAMigrateTemplate struct {
 	ContactEmail *ContactEmailElement `json:"contactEmail,omitempty"`
 	Labels       []LabelElement       `json:"labels,omitempty"`
 	Metadata     []MetadataElement    `json:"metadata,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 type WebRAMigrateRequestParams struct {
-	CertificatePEM string
-	CertificateId  string
-	Profile        string
-	Template       *WebRAMigrateTemplate
+	CertificatePEM   string
+	CertificateId    string
+	Profile          string
+	Template         *WebRAMigrateTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRAMigrateRequest struct {
@@ -630,6 +795,7 @@ type WebRAMigrateRequest struct {
 	HolderId             string                `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                   `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                   `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRAMigrateRequest) EnsureType() error {
@@ -640,39 +806,54 @@ func (r *WebRAMigrateRequest) EnsureType() error {
 	return nil
 }
 
-type WebRARecoverRequestParams struct {
-	CertificateId  string
+type WebRARecoverTemplateParams struct {
 	CertificatePEM string
-	Password       string
-	Contact        string
+	CertificateId  string
+}
+
+// Capabilities is a readonly field
+type WebRARecoverTemplate struct {
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	preservedFields
+}
+
+type WebRARecoverRequestParams struct {
+	CertificateId    string
+	CertificatePEM   string
+	Password         string
+	Contact          string
+	RequesterComment string
+	Labels           []Label
 }
 
 type WebRARecoverRequest struct {
-	Id                   string       `json:"_id,omitempty"`
-	Workflow             Workflow     `json:"workflow"`
-	Module               Module       `json:"module,omitempty"`
-	Status               Status       `json:"status,omitempty"`
-	Profile              string       `json:"profile,omitempty"`
-	Dn                   string       `json:"dn,omitempty"`
-	Requester            string       `json:"requester,omitempty"`
-	Approver             string       `json:"approver,omitempty"`
-	Contact              string       `json:"contact,omitempty"`
-	RequesterComment     string       `json:"requesterComment,omitempty"`
-	ApproverComment      string       `json:"approverComment,omitempty"`
-	RegistrationDate     int64        `json:"registrationDate"`
-	LastModificationDate int64        `json:"lastModificationDate"`
-	ExpirationDate       int64        `json:"expirationDate"`
-	RemoveAt             int64        `json:"removeAt"`
-	CertificatePEM       string       `json:"certificatePem,omitempty"`
-	CertificateId        string       `json:"certificateId,omitempty"`
-	Certificate          *Certificate `json:"certificate,omitempty"`
-	Pkcs12               *Secret      `json:"pkcs12,omitempty"`
-	Password             *Secret      `json:"password,omitempty"`
-	Labels               []Label      `json:"labels,omitempty"`
-	Metadata             []Metadata   `json:"metadata,omitempty"`
-	HolderId             string       `json:"holderId,omitempty"`
-	GlobalHolderIdCount  int          `json:"globalHolderIdCount,omitempty"`
-	ProfileHolderIdCount int          `json:"profileHolderIdCount,omitempty"`
+	Id                   string                `json:"_id,omitempty"`
+	Workflow             Workflow              `json:"workflow"`
+	Module               Module                `json:"module,omitempty"`
+	Status               Status                `json:"status,omitempty"`
+	Profile              string                `json:"profile,omitempty"`
+	Dn                   string                `json:"dn,omitempty"`
+	Requester            string                `json:"requester,omitempty"`
+	Approver             string                `json:"approver,omitempty"`
+	Contact              string                `json:"contact,omitempty"`
+	RequesterComment     string                `json:"requesterComment,omitempty"`
+	ApproverComment      string                `json:"approverComment,omitempty"`
+	RegistrationDate     int64                 `json:"registrationDate"`
+	LastModificationDate int64                 `json:"lastModificationDate"`
+	ExpirationDate       int64                 `json:"expirationDate"`
+	RemoveAt             int64                 `json:"removeAt"`
+	Template             *WebRARecoverTemplate `json:"template,omitempty"`
+	CertificatePEM       string                `json:"certificatePem,omitempty"`
+	CertificateId        string                `json:"certificateId,omitempty"`
+	Certificate          *Certificate          `json:"certificate,omitempty"`
+	Pkcs12               *Secret               `json:"pkcs12,omitempty"`
+	Password             *Secret               `json:"password,omitempty"`
+	Labels               []Label               `json:"labels,omitempty"`
+	Metadata             []Metadata            `json:"metadata,omitempty"`
+	HolderId             string                `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                   `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                   `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRARecoverRequest) EnsureType() error {
@@ -704,13 +885,18 @@ type WebRAImportTemplate struct {
 	ThirdPartyData []ThirdPartyItem     `json:"thirdPartyData,omitempty"`
 	DiscoveryData  *DiscoveryData       `json:"discoveryData,omitempty"`
 	DiscoveryInfo  *DiscoveryInfo       `json:"discoveryInfo,omitempty"`
+	preservedFields
+	serverTemplate
 }
 
 type WebRAImportRequestParams struct {
-	CertificatePEM string
-	CertificateId  string
-	Profile        string
-	Template       *WebRAImportTemplate
+	CertificatePEM   string
+	CertificateId    string
+	Profile          string
+	Template         *WebRAImportTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
 }
 
 type WebRAImportRequest struct {
@@ -739,6 +925,7 @@ type WebRAImportRequest struct {
 	HolderId             string               `json:"holderId,omitempty"`
 	GlobalHolderIdCount  int                  `json:"globalHolderIdCount,omitempty"`
 	ProfileHolderIdCount int                  `json:"profileHolderIdCount,omitempty"`
+	preservedFields
 }
 
 func (r *WebRAImportRequest) EnsureType() error {
@@ -749,6 +936,199 @@ func (r *WebRAImportRequest) EnsureType() error {
 	return nil
 }
 
+type AcmeEnrollTemplateParams struct {
+	Profile string
+	Csr     string
+}
+
+// Capabilities is a readonly field
+type AcmeEnrollTemplate struct {
+	Csr          string               `json:"csr,omitempty"`
+	Subject      []IndexedDNElement   `json:"subject,omitempty"`
+	Sans         []ListSANElement     `json:"sans,omitempty"`
+	Extensions   []ExtensionElement   `json:"extensions,omitempty"`
+	Owner        *OwnerElement        `json:"owner,omitempty"`
+	Team         *TeamElement         `json:"team,omitempty"`
+	ContactEmail *ContactEmailElement `json:"contactEmail,omitempty"`
+	Labels       []LabelElement       `json:"labels,omitempty"`
+	Metadata     []MetadataElement    `json:"metadata,omitempty"`
+	Capabilities *Capabilities        `json:"capabilities,omitempty"`
+	preservedFields
+	serverTemplate
+}
+
+type AcmeEnrollRequestParams struct {
+	Profile          string
+	Template         *AcmeEnrollTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
+}
+
+type AcmeEnrollRequest struct {
+	Id                   string              `json:"_id,omitempty"`
+	Workflow             Workflow            `json:"workflow"`
+	Module               Module              `json:"module,omitempty"`
+	Status               Status              `json:"status,omitempty"`
+	Profile              string              `json:"profile,omitempty"`
+	Dn                   string              `json:"dn,omitempty"`
+	Requester            string              `json:"requester,omitempty"`
+	Approver             string              `json:"approver,omitempty"`
+	Contact              string              `json:"contact,omitempty"`
+	RequesterComment     string              `json:"requesterComment,omitempty"`
+	ApproverComment      string              `json:"approverComment,omitempty"`
+	RegistrationDate     int64               `json:"registrationDate"`
+	LastModificationDate int64               `json:"lastModificationDate"`
+	ExpirationDate       int64               `json:"expirationDate"`
+	RemoveAt             int64               `json:"removeAt"`
+	Template             *AcmeEnrollTemplate `json:"template,omitempty"`
+	CertificatePEM       string              `json:"certificatePem,omitempty"`
+	CertificateId        string              `json:"certificateId,omitempty"`
+	Certificate          *Certificate        `json:"certificate,omitempty"`
+	Labels               []Label             `json:"labels,omitempty"`
+	Metadata             []Metadata          `json:"metadata,omitempty"`
+	HolderId             string              `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                 `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                 `json:"profileHolderIdCount,omitempty"`
+	preservedFields
+}
+
+func (r *AcmeEnrollRequest) EnsureType() error {
+	if r.Module != Acme {
+		return invalidModuleError(r.Module, Acme)
+	}
+	if r.Workflow != Enroll {
+		return invalidWorkflowError(r.Workflow, Enroll)
+	}
+	return nil
+}
+
+type AcmeExternalEnrollTemplateParams struct {
+	Profile string
+	Csr     string
+}
+
+// Capabilities is a readonly field
+type AcmeExternalEnrollTemplate struct {
+	Csr          string               `json:"csr,omitempty"`
+	Subject      []IndexedDNElement   `json:"subject,omitempty"`
+	Sans         []ListSANElement     `json:"sans,omitempty"`
+	Extensions   []ExtensionElement   `json:"extensions,omitempty"`
+	Owner        *OwnerElement        `json:"owner,omitempty"`
+	Team         *TeamElement         `json:"team,omitempty"`
+	ContactEmail *ContactEmailElement `json:"contactEmail,omitempty"`
+	Labels       []LabelElement       `json:"labels,omitempty"`
+	Metadata     []MetadataElement    `json:"metadata,omitempty"`
+	Capabilities *Capabilities        `json:"capabilities,omitempty"`
+	preservedFields
+	serverTemplate
+}
+
+type AcmeExternalEnrollRequestParams struct {
+	Profile          string
+	Template         *AcmeExternalEnrollTemplate
+	RequesterComment string
+	Contact          string
+	Labels           []Label
+}
+
+type AcmeExternalEnrollRequest struct {
+	Id                   string                      `json:"_id,omitempty"`
+	Workflow             Workflow                    `json:"workflow"`
+	Module               Module                      `json:"module,omitempty"`
+	Status               Status                      `json:"status,omitempty"`
+	Profile              string                      `json:"profile,omitempty"`
+	Dn                   string                      `json:"dn,omitempty"`
+	Requester            string                      `json:"requester,omitempty"`
+	Approver             string                      `json:"approver,omitempty"`
+	Contact              string                      `json:"contact,omitempty"`
+	RequesterComment     string                      `json:"requesterComment,omitempty"`
+	ApproverComment      string                      `json:"approverComment,omitempty"`
+	RegistrationDate     int64                       `json:"registrationDate"`
+	LastModificationDate int64                       `json:"lastModificationDate"`
+	ExpirationDate       int64                       `json:"expirationDate"`
+	RemoveAt             int64                       `json:"removeAt"`
+	Template             *AcmeExternalEnrollTemplate `json:"template,omitempty"`
+	CertificatePEM       string                      `json:"certificatePem,omitempty"`
+	CertificateId        string                      `json:"certificateId,omitempty"`
+	Certificate          *Certificate                `json:"certificate,omitempty"`
+	Labels               []Label                     `json:"labels,omitempty"`
+	Metadata             []Metadata                  `json:"metadata,omitempty"`
+	HolderId             string                      `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                         `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                         `json:"profileHolderIdCount,omitempty"`
+	preservedFields
+}
+
+func (r *AcmeExternalEnrollRequest) EnsureType() error {
+	if r.Module != AcmeExternal {
+		return invalidModuleError(r.Module, AcmeExternal)
+	}
+	if r.Workflow != Enroll {
+		return invalidWorkflowError(r.Workflow, Enroll)
+	}
+	return nil
+}
+
+type AcmeExternalRenewTemplateParams struct {
+	CertificatePEM string
+	CertificateId  string
+}
+
+// Capabilities is a readonly field
+type AcmeExternalRenewTemplate struct {
+	Csr          string        `json:"csr,omitempty"`
+	Capabilities *Capabilities `json:"capabilities,omitempty"`
+	preservedFields
+}
+
+type AcmeExternalRenewRequestParams struct {
+	Template         *AcmeExternalRenewTemplate
+	CertToRenewId    string
+	CertToRenewPem   string
+	RequesterComment string
+	Contact          string
+	Labels           []Label
+}
+
+type AcmeExternalRenewRequest struct {
+	Id                   string                     `json:"_id,omitempty"`
+	Workflow             Workflow                   `json:"workflow"`
+	Module               Module                     `json:"module,omitempty"`
+	Status               Status                     `json:"status,omitempty"`
+	Profile              string                     `json:"profile,omitempty"`
+	Dn                   string                     `json:"dn,omitempty"`
+	Requester            string                     `json:"requester,omitempty"`
+	Approver             string                     `json:"approver,omitempty"`
+	Contact              string                     `json:"contact,omitempty"`
+	RequesterComment     string                     `json:"requesterComment,omitempty"`
+	ApproverComment      string                     `json:"approverComment,omitempty"`
+	RegistrationDate     int64                      `json:"registrationDate"`
+	LastModificationDate int64                      `json:"lastModificationDate"`
+	ExpirationDate       int64                      `json:"expirationDate"`
+	RemoveAt             int64                      `json:"removeAt"`
+	Template             *AcmeExternalRenewTemplate `json:"template,omitempty"`
+	CertificatePEM       string                     `json:"certificatePem,omitempty"`
+	CertificateId        string                     `json:"certificateId,omitempty"`
+	Certificate          *Certificate               `json:"certificate,omitempty"`
+	Labels               []Label                    `json:"labels,omitempty"`
+	Metadata             []Metadata                 `json:"metadata,omitempty"`
+	HolderId             string                     `json:"holderId,omitempty"`
+	GlobalHolderIdCount  int                        `json:"globalHolderIdCount,omitempty"`
+	ProfileHolderIdCount int                        `json:"profileHolderIdCount,omitempty"`
+	preservedFields
+}
+
+func (r *AcmeExternalRenewRequest) EnsureType() error {
+	if r.Module != AcmeExternal {
+		return invalidModuleError(r.Module, AcmeExternal)
+	}
+	if r.Workflow != Renew {
+		return invalidWorkflowError(r.Workflow, Renew)
+	}
+	return nil
+}
+
 type SearchScope string
 
 const (
@@ -0,0 +1,307 @@
+package requests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Bulk submission
+
+// BulkItem is a request to submit as part of a batch.
+// Key must identify the item across runs (e.g. the ID of the certificate to renew) so that a batch can be resumed.
+type BulkItem struct {
+	Key    string
+	Submit func(c *Client) (horizon.Request, error)
+}
+
+func EnrollItem(key string, params horizon.WebRAEnrollRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewEnrollRequest(params) }}
+}
+
+func RenewItem(key string, params horizon.WebRARenewRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewRenewRequest(params) }}
+}
+
+func RevokeItem(key string, params horizon.WebRARevokeRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewRevokeRequest(params) }}
+}
+
+func UpdateItem(key string, params horizon.WebRAUpdateRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewUpdateRequest(params) }}
+}
+
+func MigrateItem(key string, params horizon.WebRAMigrateRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewMigrateRequest(params) }}
+}
+
+func ImportItem(key string, params horizon.WebRAImportRequestParams) BulkItem {
+	return BulkItem{Key: key, Submit: func(c *Client) (horizon.Request, error) { return c.NewImportRequest(params) }}
+}
+
+// BulkResult is the outcome of a BulkItem.
+// Err holds the error returned by Horizon (usually a *http.HorizonErrorResponse) if the submission failed.
+// If the item was already submitted in a previous run, or by an earlier item of the batch with the same key, Skipped is true and only RequestId is set.
+type BulkResult struct {
+	Key       string
+	RequestId string
+	Request   horizon.Request
+	Skipped   bool
+	Err       error
+}
+
+// Checkpoint records the items successfully submitted, so that an interrupted batch can be restarted without duplicates
+type Checkpoint interface {
+	// Load returns the ID of the request submitted for the key, if any
+	Load(key string) (requestId string, found bool, err error)
+	Save(key string, requestId string) error
+}
+
+// MemoryCheckpoint is a Checkpoint kept in memory, useful to retry failed items within the same process
+type MemoryCheckpoint struct {
+	mutex sync.Mutex
+	done  map[string]string
+}
+
+func NewMemoryCheckpoint() *MemoryCheckpoint {
+	return &MemoryCheckpoint{done: make(map[string]string)}
+}
+
+func (m *MemoryCheckpoint) Load(key string) (string, bool, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	requestId, found := m.done[key]
+	return requestId, found, nil
+}
+
+func (m *MemoryCheckpoint) Save(key string, requestId string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.done[key] = requestId
+	return nil
+}
+
+type checkpointEntry struct {
+	Key       string `json:"key"`
+	RequestId string `json:"requestId"`
+}
+
+// FileCheckpoint is a Checkpoint persisted as a JSON Lines file, appended to after each successful submission
+type FileCheckpoint struct {
+	memory *MemoryCheckpoint
+	mutex  sync.Mutex
+	file   *os.File
+}
+
+// NewFileCheckpoint opens (or creates) the checkpoint file at path and loads the items it already records.
+// A truncated last line, left by a crash, is removed so that the next entries are appended on their own line.
+func NewFileCheckpoint(path string) (*FileCheckpoint, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	checkpoint := FileCheckpoint{memory: NewMemoryCheckpoint(), file: file}
+	if err := checkpoint.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return &checkpoint, nil
+}
+
+func (f *FileCheckpoint) load() error {
+	data, err := io.ReadAll(f.file)
+	if err != nil {
+		return err
+	}
+	var entry checkpointEntry
+	for _, line := range bytes.Split(data, []byte{'\n'}) {
+		if json.Unmarshal(line, &entry) == nil && entry.Key != "" {
+			f.memory.done[entry.Key] = entry.RequestId
+		}
+	}
+	if len(data) == 0 || data[len(data)-1] == '\n' {
+		return nil
+	}
+	last := data[bytes.LastIndexByte(data, '\n')+1:]
+	if json.Unmarshal(last, &checkpointEntry{}) == nil {
+		// The last entry is complete but was not terminated
+		_, err = f.file.Write([]byte{'\n'})
+		return err
+	}
+	return f.file.Truncate(int64(len(data) - len(last)))
+}
+
+func (f *FileCheckpoint) Load(key string) (string, bool, error) {
+	return f.memory.Load(key)
+}
+
+func (f *FileCheckpoint) Save(key string, requestId string) error {
+	line, err := json.Marshal(checkpointEntry{Key: key, RequestId: requestId})
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if _, err := f.file.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	return f.memory.Save(key, requestId)
+}
+
+func (f *FileCheckpoint) Close() error {
+	return f.file.Close()
+}
+
+type BulkOptions struct {
+	// Concurrency is the maximum number of requests submitted in parallel, defaults to 1
+	Concurrency int
+	// Checkpoint is optional. Items already recorded in it are skipped
+	Checkpoint Checkpoint
+}
+
+// SubmitBulk submits the items received on the channel using a bounded pool of workers.
+// The results channel is closed once the items channel is closed and every item has been processed, or the context is canceled.
+// Results are sent in completion order.
+func (c *Client) SubmitBulk(ctx context.Context, items <-chan BulkItem, options BulkOptions) <-chan BulkResult {
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make(chan BulkResult)
+	batch := bulkBatch{submissions: make(map[string]*bulkSubmission)}
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				var item BulkItem
+				var ok bool
+				select {
+				case <-ctx.Done():
+					return
+				case item, ok = <-items:
+					if !ok || ctx.Err() != nil {
+						return
+					}
+				}
+				select {
+				case results <- batch.submit(ctx, c, item, options.Checkpoint):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+	return results
+}
+
+// SubmitBulkSlice submits the items using a bounded pool of workers and returns the results in the order of the items.
+// Items that could not be processed because the context was canceled have their Err set to the context error.
+func (c *Client) SubmitBulkSlice(ctx context.Context, items []BulkItem, options BulkOptions) []BulkResult {
+	indexes := make(map[string][]int)
+	for i, item := range items {
+		indexes[item.Key] = append(indexes[item.Key], i)
+	}
+	queue := make(chan BulkItem)
+	go func() {
+		defer close(queue)
+		for _, item := range items {
+			select {
+			case queue <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	results := make([]BulkResult, len(items))
+	processed := make([]bool, len(items))
+	for result := range c.SubmitBulk(ctx, queue, options) {
+		for _, i := range indexes[result.Key] {
+			if !processed[i] {
+				results[i] = result
+				processed[i] = true
+				break
+			}
+		}
+	}
+	for i, item := range items {
+		if !processed[i] {
+			results[i] = BulkResult{Key: item.Key, Err: ctx.Err()}
+		}
+	}
+	return results
+}
+
+// bulkBatch deduplicates the keys of a batch: an item whose key is already being submitted waits for the first submission
+type bulkBatch struct {
+	mutex       sync.Mutex
+	submissions map[string]*bulkSubmission
+}
+
+type bulkSubmission struct {
+	done   chan struct{}
+	result BulkResult
+}
+
+func (b *bulkBatch) submit(ctx context.Context, c *Client, item BulkItem, checkpoint Checkpoint) BulkResult {
+	b.mutex.Lock()
+	submission, duplicate := b.submissions[item.Key]
+	if !duplicate {
+		submission = &bulkSubmission{done: make(chan struct{})}
+		b.submissions[item.Key] = submission
+	}
+	b.mutex.Unlock()
+	if !duplicate {
+		submission.result = c.submitBulkItem(item, checkpoint)
+		close(submission.done)
+		return submission.result
+	}
+	select {
+	case <-submission.done:
+	case <-ctx.Done():
+		return BulkResult{Key: item.Key, Err: ctx.Err()}
+	}
+	return BulkResult{Key: item.Key, RequestId: submission.result.RequestId, Skipped: submission.result.Err == nil, Err: submission.result.Err}
+}
+
+func (c *Client) submitBulkItem(item BulkItem, checkpoint Checkpoint) BulkResult {
+	result := BulkResult{Key: item.Key}
+	if item.Submit == nil {
+		result.Err = errors.New("bulk item has nothing to submit")
+		return result
+	}
+	if checkpoint != nil {
+		requestId, found, err := checkpoint.Load(item.Key)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if found {
+			result.RequestId = requestId
+			result.Skipped = true
+			return result
+		}
+	}
+	request, err := item.Submit(c)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Request = request
+	result.RequestId = request.GetId()
+	if checkpoint != nil {
+		result.Err = checkpoint.Save(item.Key, result.RequestId)
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package requests
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func renewItems(keys ...string) []BulkItem {
+	var items []BulkItem
+	for _, key := range keys {
+		items = append(items, RenewItem(key, horizon.WebRARenewRequestParams{CertToRenewId: key}))
+	}
+	return items
+}
+
+func TestSubmitBulkSlice(t *testing.T) {
+	c, calls := newMockClient(t)
+	checkpoint := NewMemoryCheckpoint()
+	_ = checkpoint.Save("cert-2", "previous-request")
+	results := c.SubmitBulkSlice(context.Background(), renewItems("cert-1", "cert-2", "cert-3", "cert-4"), BulkOptions{
+		Concurrency: 3,
+		Checkpoint:  checkpoint,
+	})
+	if len(*calls) != 3 {
+		t.Errorf("expected 3 submissions, got %d", len(*calls))
+	}
+	for i, result := range results {
+		if result.Err != nil {
+			t.Fatal(result.Err.Error())
+		}
+		if result.Key != renewItems("cert-1", "cert-2", "cert-3", "cert-4")[i].Key {
+			t.Errorf("results are not in the order of the items")
+		}
+		if result.RequestId == "" {
+			t.Errorf("missing request ID for %s", result.Key)
+		}
+	}
+	if !results[1].Skipped || results[1].RequestId != "previous-request" {
+		t.Errorf("cert-2 should have been skipped, got %+v", results[1])
+	}
+	if _, ok := results[0].Request.(*horizon.WebRARenewRequest); !ok {
+		t.Errorf("expected a renew request, got %T", results[0].Request)
+	}
+}
+
+func TestFileCheckpointResume(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	c, calls := newMockClient(t)
+	checkpoint, err := NewFileCheckpoint(path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	c.SubmitBulkSlice(context.Background(), renewItems("cert-1", "cert-2"), BulkOptions{Checkpoint: checkpoint})
+	_ = checkpoint.Close()
+
+	// Restarting the batch with more items only submits the new ones
+	checkpoint, err = NewFileCheckpoint(path)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer checkpoint.Close()
+	results := c.SubmitBulkSlice(context.Background(), renewItems("cert-1", "cert-2", "cert-3"), BulkOptions{Checkpoint: checkpoint})
+	if len(*calls) != 3 {
+		t.Errorf("expected 3 submissions overall, got %d", len(*calls))
+	}
+	if !results[0].Skipped || !results[1].Skipped || results[2].Skipped {
+		t.Errorf("unexpected skipped items: %+v", results)
+	}
+}
+
+func TestFileCheckpointTruncatedLine(t *testing.T) {
+	for name, content := range map[string]string{
+		"truncated":    `{"key":"cert-1","requestId":"request-1"}` + "\n" + `{"key":"cert-2","requ`,
+		"unterminated": `{"key":"cert-1","requestId":"request-1"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+			if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+				t.Fatal(err.Error())
+			}
+			checkpoint, err := NewFileCheckpoint(path)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if err := checkpoint.Save("cert-3", "request-3"); err != nil {
+				t.Fatal(err.Error())
+			}
+			_ = checkpoint.Close()
+
+			checkpoint, err = NewFileCheckpoint(path)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			defer checkpoint.Close()
+			for key, expected := range map[string]bool{"cert-1": true, "cert-2": false, "cert-3": true} {
+				if _, found, _ := checkpoint.Load(key); found != expected {
+					t.Errorf("%s should be recorded: %v", key, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestSubmitBulkDuplicateKeys(t *testing.T) {
+	c, calls := newMockClient(t)
+	results := c.SubmitBulkSlice(context.Background(), renewItems("cert-1", "cert-1", "cert-2"), BulkOptions{Concurrency: 3})
+	if len(*calls) != 2 {
+		t.Errorf("expected 2 submissions, got %d", len(*calls))
+	}
+	if results[0].Err != nil || results[1].Err != nil || results[0].Skipped == results[1].Skipped || results[0].RequestId != results[1].RequestId {
+		t.Errorf("cert-1 should be submitted once, got %+v and %+v", results[0], results[1])
+	}
+}
+
+func TestSubmitBulkCanceled(t *testing.T) {
+	c, _ := newMockClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results := c.SubmitBulkSlice(ctx, renewItems("cert-1", "cert-2"), BulkOptions{Concurrency: 2})
+	for _, result := range results {
+		if result.Err != context.Canceled {
+			t.Errorf("expected canceled items, got %+v", result)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"github.com/evertrust/horizon-go"
+)
+
+// Challenges
+
+// IssueScepChallenge requests a SCEP challenge for a profile.
+// The template is fetched if none is given, and a Dn is required if the profile is DN whitelisted.
+func (c *Client) IssueScepChallenge(request horizon.ScepChallengeRequestParams) (*horizon.Challenge, error) {
+	if request.Template == nil {
+		template, err := c.GetScepChallengeTemplate(horizon.ScepChallengeTemplateParams{Profile: request.Profile})
+		if err != nil {
+			return nil, err
+		}
+		request.Template = template
+	}
+	if request.Template != nil && request.Template.IsDnWhitelist() && request.Dn == "" {
+		return nil, horizon.DnRequiredError
+	}
+	challengeRequest, err := c.NewScepChallengeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return challengeRequest.GetChallenge(), nil
+}
+
+// IssueEstChallenge requests an EST challenge for a profile.
+// The template is fetched if none is given, and a Dn is required if the profile is DN whitelisted.
+func (c *Client) IssueEstChallenge(request horizon.EstChallengeRequestParams) (*horizon.Challenge, error) {
+	if request.Template == nil {
+		template, err := c.GetEstChallengeTemplate(horizon.EstChallengeTemplateParams{Profile: request.Profile})
+		if err != nil {
+			return nil, err
+		}
+		request.Template = template
+	}
+	if request.Template != nil && request.Template.IsDnWhitelist() && request.Dn == "" {
+		return nil, horizon.DnRequiredError
+	}
+	challengeRequest, err := c.NewEstChallengeRequest(request)
+	if err != nil {
+		return nil, err
+	}
+	return challengeRequest.GetChallenge(), nil
+}
@@ -0,0 +1,44 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	gohttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestIssueChallenge(t *testing.T) {
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var request map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		switch r.URL.Path {
+		case "/api/v1/requests/template":
+			request["template"] = map[string]interface{}{"dnWhitelist": true}
+		case "/api/v1/requests/submit":
+			request["_id"] = "id"
+			request["status"] = "completed"
+			request["password"] = map[string]string{"value": "challenge"}
+			request["expirationDate"] = time.Now().Add(time.Hour).UnixMilli()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(request)
+	})
+
+	if _, err := client.IssueScepChallenge(horizon.ScepChallengeRequestParams{Profile: "scep"}); !errors.Is(err, horizon.DnRequiredError) {
+		t.Errorf("a DN should be required, got %v", err)
+	}
+	challenge, err := client.IssueEstChallenge(horizon.EstChallengeRequestParams{Profile: "est", Dn: "CN=device"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if challenge.Value != "challenge" || challenge.Dn != "CN=device" || challenge.Module != horizon.Est || !challenge.IsReady() {
+		t.Errorf("unexpected challenge %+v", challenge)
+	}
+	challenge, err = client.IssueScepChallenge(horizon.ScepChallengeRequestParams{Profile: "scep", Dn: "CN=device"})
+	if err != nil || challenge.Module != horizon.Scep || challenge.RequestId != "id" {
+		t.Errorf("unexpected challenge %+v (%v)", challenge, err)
+	}
+}
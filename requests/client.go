@@ -4,6 +4,7 @@ package requests
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/evertrust/horizon-go"
 	"github.com/evertrust/horizon-go/http"
 )
@@ -79,11 +80,14 @@ func (c *Client) NewEnrollRequest(request horizon.WebRAEnrollRequestParams) (*ho
 		password.Value = request.Password
 	}
 	enrollRequest := horizon.WebRAEnrollRequest{
-		Profile:  request.Profile,
-		Template: request.Template,
-		Module:   horizon.WebRA,
-		Workflow: horizon.Enroll,
-		Password: password,
+		Profile:          request.Profile,
+		Template:         request.Template,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Enroll,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+		Password:         password,
 	}
 	err := c.NewRequest(&enrollRequest)
 	if err != nil {
@@ -119,11 +123,84 @@ func (c *Client) GetScepChallengeRequest(id string) (*horizon.ScepChallengeReque
 
 func (c *Client) NewScepChallengeRequest(request horizon.ScepChallengeRequestParams) (*horizon.ScepChallengeRequest, error) {
 	challengeRequest := horizon.ScepChallengeRequest{
-		Profile:  request.Profile,
-		Template: request.Template,
+		Profile:          request.Profile,
+		Template:         request.Template,
+		Module:           horizon.Scep,
+		Workflow:         horizon.Enroll,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+		Dn:               request.Dn,
+	}
+	err := c.NewRequest(&challengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &challengeRequest, nil
+}
+
+func (c *Client) CancelScepChallengeRequest(id string) (*horizon.ScepChallengeRequest, error) {
+	scepChallengeRequest := horizon.ScepChallengeRequest{
 		Module:   horizon.Scep,
 		Workflow: horizon.Enroll,
-		Dn:       request.Dn,
+		Id:       id,
+	}
+	err := c.CancelRequest(&scepChallengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &scepChallengeRequest, nil
+}
+
+// SCEP Challenge renewal
+
+func (c *Client) GetScepChallengeRenewTemplate(request horizon.ScepChallengeRenewTemplateParams) (*horizon.ScepChallengeTemplate, error) {
+	// Merge params in struct
+	challengeRequest := horizon.ScepChallengeRenewRequest{
+		CertificateId:  request.CertificateId,
+		CertificatePEM: request.CertificatePEM,
+		Module:         horizon.Scep,
+		Workflow:       horizon.Renew}
+	err := c.GetTemplate(&challengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return challengeRequest.Template, nil
+}
+
+func (c *Client) GetScepChallengeRenewRequest(id string) (*horizon.ScepChallengeRenewRequest, error) {
+	var scepChallengeRenewRequest horizon.ScepChallengeRenewRequest
+	// Merge params in struct
+	err := c.GetRequest(id, &scepChallengeRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &scepChallengeRenewRequest, nil
+}
+
+func (c *Client) CancelScepChallengeRenewRequest(id string) (*horizon.ScepChallengeRenewRequest, error) {
+	scepChallengeRenewRequest := horizon.ScepChallengeRenewRequest{
+		Module:   horizon.Scep,
+		Workflow: horizon.Renew,
+		Id:       id,
+	}
+	err := c.CancelRequest(&scepChallengeRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &scepChallengeRenewRequest, nil
+}
+
+func (c *Client) NewScepChallengeRenewRequest(request horizon.ScepChallengeRenewRequestParams) (*horizon.ScepChallengeRenewRequest, error) {
+	challengeRequest := horizon.ScepChallengeRenewRequest{
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Template:         request.Template,
+		Module:           horizon.Scep,
+		Workflow:         horizon.Renew,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&challengeRequest)
 	if err != nil {
@@ -159,11 +236,84 @@ func (c *Client) GetEstChallengeRequest(id string) (*horizon.EstChallengeRequest
 
 func (c *Client) NewEstChallengeRequest(request horizon.EstChallengeRequestParams) (*horizon.EstChallengeRequest, error) {
 	challengeRequest := horizon.EstChallengeRequest{
-		Profile:  request.Profile,
-		Template: request.Template,
+		Profile:          request.Profile,
+		Template:         request.Template,
+		Module:           horizon.Est,
+		Workflow:         horizon.Enroll,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+		Dn:               request.Dn,
+	}
+	err := c.NewRequest(&challengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &challengeRequest, nil
+}
+
+func (c *Client) CancelEstChallengeRequest(id string) (*horizon.EstChallengeRequest, error) {
+	estChallengeRequest := horizon.EstChallengeRequest{
 		Module:   horizon.Est,
 		Workflow: horizon.Enroll,
-		Dn:       request.Dn,
+		Id:       id,
+	}
+	err := c.CancelRequest(&estChallengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &estChallengeRequest, nil
+}
+
+// EST Challenge renewal
+
+func (c *Client) GetEstChallengeRenewTemplate(request horizon.EstChallengeRenewTemplateParams) (*horizon.EstChallengeTemplate, error) {
+	// Merge params in struct
+	challengeRequest := horizon.EstChallengeRenewRequest{
+		CertificateId:  request.CertificateId,
+		CertificatePEM: request.CertificatePEM,
+		Module:         horizon.Est,
+		Workflow:       horizon.Renew}
+	err := c.GetTemplate(&challengeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return challengeRequest.Template, nil
+}
+
+func (c *Client) GetEstChallengeRenewRequest(id string) (*horizon.EstChallengeRenewRequest, error) {
+	var estChallengeRenewRequest horizon.EstChallengeRenewRequest
+	// Merge params in struct
+	err := c.GetRequest(id, &estChallengeRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &estChallengeRenewRequest, nil
+}
+
+func (c *Client) CancelEstChallengeRenewRequest(id string) (*horizon.EstChallengeRenewRequest, error) {
+	estChallengeRenewRequest := horizon.EstChallengeRenewRequest{
+		Module:   horizon.Est,
+		Workflow: horizon.Renew,
+		Id:       id,
+	}
+	err := c.CancelRequest(&estChallengeRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &estChallengeRenewRequest, nil
+}
+
+func (c *Client) NewEstChallengeRenewRequest(request horizon.EstChallengeRenewRequestParams) (*horizon.EstChallengeRenewRequest, error) {
+	challengeRequest := horizon.EstChallengeRenewRequest{
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Template:         request.Template,
+		Module:           horizon.Est,
+		Workflow:         horizon.Renew,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&challengeRequest)
 	if err != nil {
@@ -219,12 +369,15 @@ func (c *Client) NewRenewRequest(request horizon.WebRARenewRequestParams) (*hori
 		password.Value = request.Password
 	}
 	renewRequest := horizon.WebRARenewRequest{
-		Template:       request.Template,
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Renew,
-		Password:       password,
-		CertificateId:  request.CertToRenewId,
-		CertificatePEM: request.CertToRenewPem,
+		Template:         request.Template,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Renew,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+		Password:         password,
+		CertificateId:    request.CertToRenewId,
+		CertificatePEM:   request.CertToRenewPem,
 	}
 	err := c.NewRequest(&renewRequest)
 	if err != nil {
@@ -235,6 +388,20 @@ func (c *Client) NewRenewRequest(request horizon.WebRARenewRequestParams) (*hori
 
 // WebRA Revoke
 
+func (c *Client) GetRevokeTemplate(request horizon.WebRARevokeTemplateParams) (*horizon.WebRARevokeTemplate, error) {
+	// Merge params in struct
+	revokeRequest := horizon.WebRARevokeRequest{
+		CertificateId:  request.CertificateId,
+		CertificatePEM: request.CertificatePEM,
+		Module:         horizon.WebRA,
+		Workflow:       horizon.Revoke}
+	err := c.GetTemplate(&revokeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return revokeRequest.Template, nil
+}
+
 func (c *Client) GetRevokeRequest(id string) (*horizon.WebRARevokeRequest, error) {
 	var webRARevokeRequest horizon.WebRARevokeRequest
 	// Merge params in struct
@@ -245,14 +412,30 @@ func (c *Client) GetRevokeRequest(id string) (*horizon.WebRARevokeRequest, error
 	return &webRARevokeRequest, nil
 }
 
+func (c *Client) CancelRevokeRequest(id string) (*horizon.WebRARevokeRequest, error) {
+	webRARevokeRequest := horizon.WebRARevokeRequest{
+		Module:   horizon.WebRA,
+		Workflow: horizon.Revoke,
+		Id:       id,
+	}
+	err := c.CancelRequest(&webRARevokeRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &webRARevokeRequest, nil
+}
+
 func (c *Client) NewRevokeRequest(request horizon.WebRARevokeRequestParams) (*horizon.WebRARevokeRequest, error) {
 	// Merge params in struct
 	revokeRequest := horizon.WebRARevokeRequest{
-		CertificateId:  request.CertificateId,
-		CertificatePEM: request.CertificatePEM,
-		Template:       &horizon.WebRARevokeTemplate{RevocationReason: request.RevocationReason},
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Revoke,
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Template:         &horizon.WebRARevokeTemplate{RevocationReason: request.RevocationReason},
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Revoke,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&revokeRequest)
 	if err != nil {
@@ -287,13 +470,29 @@ func (c *Client) GetUpdateRequest(id string) (*horizon.WebRAUpdateRequest, error
 	return &webRAUpdateRequest, nil
 }
 
+func (c *Client) CancelUpdateRequest(id string) (*horizon.WebRAUpdateRequest, error) {
+	webRAUpdateRequest := horizon.WebRAUpdateRequest{
+		Module:   horizon.WebRA,
+		Workflow: horizon.Update,
+		Id:       id,
+	}
+	err := c.CancelRequest(&webRAUpdateRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &webRAUpdateRequest, nil
+}
+
 func (c *Client) NewUpdateRequest(request horizon.WebRAUpdateRequestParams) (*horizon.WebRAUpdateRequest, error) {
 	updateRequest := horizon.WebRAUpdateRequest{
-		CertificateId:  request.CertificateId,
-		CertificatePEM: request.CertificatePEM,
-		Template:       request.Template,
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Update,
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Template:         request.Template,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Update,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&updateRequest)
 	if err != nil {
@@ -329,14 +528,30 @@ func (c *Client) GetMigrateRequest(id string) (*horizon.WebRAMigrateRequest, err
 	return &webRAMigrateRequest, nil
 }
 
+func (c *Client) CancelMigrateRequest(id string) (*horizon.WebRAMigrateRequest, error) {
+	webRAMigrateRequest := horizon.WebRAMigrateRequest{
+		Module:   horizon.WebRA,
+		Workflow: horizon.Migrate,
+		Id:       id,
+	}
+	err := c.CancelRequest(&webRAMigrateRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &webRAMigrateRequest, nil
+}
+
 func (c *Client) NewMigrateRequest(request horizon.WebRAMigrateRequestParams) (*horizon.WebRAMigrateRequest, error) {
 	migrateRequest := horizon.WebRAMigrateRequest{
-		CertificateId:  request.CertificateId,
-		CertificatePEM: request.CertificatePEM,
-		Template:       request.Template,
-		Profile:        request.Profile,
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Migrate,
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Template:         request.Template,
+		Profile:          request.Profile,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Migrate,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&migrateRequest)
 	if err != nil {
@@ -373,15 +588,31 @@ func (c *Client) GetImportRequest(id string) (*horizon.WebRAImportRequest, error
 	return &webRAImportRequest, nil
 }
 
+func (c *Client) CancelImportRequest(id string) (*horizon.WebRAImportRequest, error) {
+	webRAImportRequest := horizon.WebRAImportRequest{
+		Module:   horizon.WebRA,
+		Workflow: horizon.Import,
+		Id:       id,
+	}
+	err := c.CancelRequest(&webRAImportRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &webRAImportRequest, nil
+}
+
 func (c *Client) NewImportRequest(request horizon.WebRAImportRequestParams) (*horizon.WebRAImportRequest, error) {
 	// Merge params in struct
 	importRequest := horizon.WebRAImportRequest{
-		Profile:        request.Profile,
-		Template:       request.Template,
-		CertificatePEM: request.CertificatePEM,
-		CertificateId:  request.CertificateId,
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Import,
+		Profile:          request.Profile,
+		Template:         request.Template,
+		CertificatePEM:   request.CertificatePEM,
+		CertificateId:    request.CertificateId,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Import,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&importRequest)
 	if err != nil {
@@ -392,6 +623,20 @@ func (c *Client) NewImportRequest(request horizon.WebRAImportRequestParams) (*ho
 
 // WebRA Recover
 
+func (c *Client) GetRecoverTemplate(request horizon.WebRARecoverTemplateParams) (*horizon.WebRARecoverTemplate, error) {
+	// Merge params in struct
+	recoverRequest := horizon.WebRARecoverRequest{
+		CertificateId:  request.CertificateId,
+		CertificatePEM: request.CertificatePEM,
+		Module:         horizon.WebRA,
+		Workflow:       horizon.Recover}
+	err := c.GetTemplate(&recoverRequest)
+	if err != nil {
+		return nil, err
+	}
+	return recoverRequest.Template, nil
+}
+
 func (c *Client) GetRecoverRequest(id string) (*horizon.WebRARecoverRequest, error) {
 	var webRARecoverRequest horizon.WebRARecoverRequest
 	// Merge params in struct
@@ -402,6 +647,19 @@ func (c *Client) GetRecoverRequest(id string) (*horizon.WebRARecoverRequest, err
 	return &webRARecoverRequest, nil
 }
 
+func (c *Client) CancelRecoverRequest(id string) (*horizon.WebRARecoverRequest, error) {
+	webRARecoverRequest := horizon.WebRARecoverRequest{
+		Module:   horizon.WebRA,
+		Workflow: horizon.Recover,
+		Id:       id,
+	}
+	err := c.CancelRequest(&webRARecoverRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &webRARecoverRequest, nil
+}
+
 func (c *Client) NewRecoverRequest(request horizon.WebRARecoverRequestParams) (*horizon.WebRARecoverRequest, error) {
 	var password *horizon.Secret
 	if request.Password != "" {
@@ -409,12 +667,14 @@ func (c *Client) NewRecoverRequest(request horizon.WebRARecoverRequestParams) (*
 		password.Value = request.Password
 	}
 	recoverRequest := horizon.WebRARecoverRequest{
-		CertificateId:  request.CertificateId,
-		CertificatePEM: request.CertificatePEM,
-		Contact:        request.Contact,
-		Password:       password,
-		Module:         horizon.WebRA,
-		Workflow:       horizon.Recover,
+		CertificateId:    request.CertificateId,
+		CertificatePEM:   request.CertificatePEM,
+		Password:         password,
+		Module:           horizon.WebRA,
+		Workflow:         horizon.Recover,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
 	}
 	err := c.NewRequest(&recoverRequest)
 	if err != nil {
@@ -423,6 +683,175 @@ func (c *Client) NewRecoverRequest(request horizon.WebRARecoverRequestParams) (*
 	return &recoverRequest, nil
 }
 
+// ACME Enroll
+
+func (c *Client) GetAcmeEnrollTemplate(request horizon.AcmeEnrollTemplateParams) (*horizon.AcmeEnrollTemplate, error) {
+	// Merge params in struct
+	enrollRequest := horizon.AcmeEnrollRequest{
+		Profile:  request.Profile,
+		Template: &horizon.AcmeEnrollTemplate{Csr: request.Csr},
+		Module:   horizon.Acme,
+		Workflow: horizon.Enroll}
+	err := c.GetTemplate(&enrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return enrollRequest.Template, nil
+}
+
+func (c *Client) GetAcmeEnrollRequest(id string) (*horizon.AcmeEnrollRequest, error) {
+	var acmeEnrollRequest horizon.AcmeEnrollRequest
+	// Merge params in struct
+	err := c.GetRequest(id, &acmeEnrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeEnrollRequest, nil
+}
+
+func (c *Client) CancelAcmeEnrollRequest(id string) (*horizon.AcmeEnrollRequest, error) {
+	acmeEnrollRequest := horizon.AcmeEnrollRequest{
+		Module:   horizon.Acme,
+		Workflow: horizon.Enroll,
+		Id:       id,
+	}
+	err := c.CancelRequest(&acmeEnrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeEnrollRequest, nil
+}
+
+func (c *Client) NewAcmeEnrollRequest(request horizon.AcmeEnrollRequestParams) (*horizon.AcmeEnrollRequest, error) {
+	enrollRequest := horizon.AcmeEnrollRequest{
+		Profile:          request.Profile,
+		Template:         request.Template,
+		Module:           horizon.Acme,
+		Workflow:         horizon.Enroll,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+	}
+	err := c.NewRequest(&enrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &enrollRequest, nil
+}
+
+// External ACME Enroll
+
+func (c *Client) GetAcmeExternalEnrollTemplate(request horizon.AcmeExternalEnrollTemplateParams) (*horizon.AcmeExternalEnrollTemplate, error) {
+	// Merge params in struct
+	enrollRequest := horizon.AcmeExternalEnrollRequest{
+		Profile:  request.Profile,
+		Template: &horizon.AcmeExternalEnrollTemplate{Csr: request.Csr},
+		Module:   horizon.AcmeExternal,
+		Workflow: horizon.Enroll}
+	err := c.GetTemplate(&enrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return enrollRequest.Template, nil
+}
+
+func (c *Client) GetAcmeExternalEnrollRequest(id string) (*horizon.AcmeExternalEnrollRequest, error) {
+	var acmeExternalEnrollRequest horizon.AcmeExternalEnrollRequest
+	// Merge params in struct
+	err := c.GetRequest(id, &acmeExternalEnrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeExternalEnrollRequest, nil
+}
+
+func (c *Client) CancelAcmeExternalEnrollRequest(id string) (*horizon.AcmeExternalEnrollRequest, error) {
+	acmeExternalEnrollRequest := horizon.AcmeExternalEnrollRequest{
+		Module:   horizon.AcmeExternal,
+		Workflow: horizon.Enroll,
+		Id:       id,
+	}
+	err := c.CancelRequest(&acmeExternalEnrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeExternalEnrollRequest, nil
+}
+
+func (c *Client) NewAcmeExternalEnrollRequest(request horizon.AcmeExternalEnrollRequestParams) (*horizon.AcmeExternalEnrollRequest, error) {
+	enrollRequest := horizon.AcmeExternalEnrollRequest{
+		Profile:          request.Profile,
+		Template:         request.Template,
+		Module:           horizon.AcmeExternal,
+		Workflow:         horizon.Enroll,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+	}
+	err := c.NewRequest(&enrollRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &enrollRequest, nil
+}
+
+// External ACME Renew
+
+func (c *Client) GetAcmeExternalRenewTemplate(request horizon.AcmeExternalRenewTemplateParams) (*horizon.AcmeExternalRenewTemplate, error) {
+	// Merge params in struct
+	renewRequest := horizon.AcmeExternalRenewRequest{
+		CertificateId:  request.CertificateId,
+		CertificatePEM: request.CertificatePEM,
+		Module:         horizon.AcmeExternal,
+		Workflow:       horizon.Renew}
+	err := c.GetTemplate(&renewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return renewRequest.Template, nil
+}
+
+func (c *Client) GetAcmeExternalRenewRequest(id string) (*horizon.AcmeExternalRenewRequest, error) {
+	var acmeExternalRenewRequest horizon.AcmeExternalRenewRequest
+	// Merge params in struct
+	err := c.GetRequest(id, &acmeExternalRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeExternalRenewRequest, nil
+}
+
+func (c *Client) CancelAcmeExternalRenewRequest(id string) (*horizon.AcmeExternalRenewRequest, error) {
+	acmeExternalRenewRequest := horizon.AcmeExternalRenewRequest{
+		Module:   horizon.AcmeExternal,
+		Workflow: horizon.Renew,
+		Id:       id,
+	}
+	err := c.CancelRequest(&acmeExternalRenewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &acmeExternalRenewRequest, nil
+}
+
+func (c *Client) NewAcmeExternalRenewRequest(request horizon.AcmeExternalRenewRequestParams) (*horizon.AcmeExternalRenewRequest, error) {
+	renewRequest := horizon.AcmeExternalRenewRequest{
+		Template:         request.Template,
+		Module:           horizon.AcmeExternal,
+		Workflow:         horizon.Renew,
+		RequesterComment: request.RequesterComment,
+		Contact:          request.Contact,
+		Labels:           request.Labels,
+		CertificateId:    request.CertToRenewId,
+		CertificatePEM:   request.CertToRenewPem,
+	}
+	err := c.NewRequest(&renewRequest)
+	if err != nil {
+		return nil, err
+	}
+	return &renewRequest, nil
+}
+
 // Low level functions
 
 func (c *Client) NewRequest(request horizon.Request) error {
@@ -467,6 +896,77 @@ func (c *Client) GetRequest(id string, result horizon.Request) error {
 	return result.EnsureType()
 }
 
+// GetAnyRequest retrieves a request without knowing its type beforehand.
+// The returned value is one of the concrete request types (e.g. *horizon.WebRAEnrollRequest), depending on its module and workflow.
+func (c *Client) GetAnyRequest(id string) (horizon.Request, error) {
+	response, err := c.http.Get("/api/v1/requests/" + id)
+	if err != nil {
+		return nil, err
+	}
+	var kind struct {
+		Module   horizon.Module   `json:"module"`
+		Workflow horizon.Workflow `json:"workflow"`
+	}
+	err = response.Json().Decode(&kind)
+	if err != nil {
+		return nil, err
+	}
+	result, err := newRequestOfKind(kind.Module, kind.Workflow)
+	if err != nil {
+		return nil, err
+	}
+	err = response.Json().Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, result.EnsureType()
+}
+
+// newRequestOfKind instantiates the request type matching a module and a workflow
+func newRequestOfKind(module horizon.Module, workflow horizon.Workflow) (horizon.Request, error) {
+	switch workflow {
+	// These workflows apply to certificates of any module
+	case horizon.Revoke:
+		return &horizon.WebRARevokeRequest{}, nil
+	case horizon.Update:
+		return &horizon.WebRAUpdateRequest{}, nil
+	case horizon.Migrate:
+		return &horizon.WebRAMigrateRequest{}, nil
+	case horizon.Import:
+		return &horizon.WebRAImportRequest{}, nil
+	case horizon.Recover:
+		// Keys are only escrowed for WebRA certificates
+		if module == horizon.WebRA {
+			return &horizon.WebRARecoverRequest{}, nil
+		}
+	case horizon.Enroll:
+		switch module {
+		case horizon.WebRA:
+			return &horizon.WebRAEnrollRequest{}, nil
+		case horizon.Scep:
+			return &horizon.ScepChallengeRequest{}, nil
+		case horizon.Est:
+			return &horizon.EstChallengeRequest{}, nil
+		case horizon.Acme:
+			return &horizon.AcmeEnrollRequest{}, nil
+		case horizon.AcmeExternal:
+			return &horizon.AcmeExternalEnrollRequest{}, nil
+		}
+	case horizon.Renew:
+		switch module {
+		case horizon.WebRA:
+			return &horizon.WebRARenewRequest{}, nil
+		case horizon.Scep:
+			return &horizon.ScepChallengeRenewRequest{}, nil
+		case horizon.Est:
+			return &horizon.EstChallengeRenewRequest{}, nil
+		case horizon.AcmeExternal:
+			return &horizon.AcmeExternalRenewRequest{}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unsupported request (module '%s', workflow '%s')", InvalidTypeError, module, workflow)
+}
+
 // Request operations
 func (c *Client) CancelRequest(request horizon.Request) error {
 	jsonData, _ := json.Marshal(request)
@@ -0,0 +1,40 @@
+package requests
+
+import (
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestRequesterCommentAndContact(t *testing.T) {
+	c, _ := newMockClient(t)
+	labels := []horizon.Label{{Key: "ticket", Value: "CHG-42"}}
+	revoke, err := c.NewRevokeRequest(horizon.WebRARevokeRequestParams{
+		CertificateId:    "id",
+		RevocationReason: horizon.KeyCompromise,
+		RequesterComment: "key leaked in a public repository",
+		Contact:          "security@example.org",
+		Labels:           labels,
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var request horizon.Request = revoke
+	if request.GetRequesterComment() != "key leaked in a public repository" || request.GetContact() != "security@example.org" {
+		t.Errorf("comment and contact were not sent, got %+v", revoke)
+	}
+	if len(request.GetLabels()) != 1 || request.GetLabels()[0] != labels[0] {
+		t.Errorf("labels were not sent, got %v", request.GetLabels())
+	}
+	recoverRequest, err := c.NewRecoverRequest(horizon.WebRARecoverRequestParams{
+		CertificateId:    "id",
+		Contact:          "john@example.org",
+		RequesterComment: "lost laptop",
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if recoverRequest.RequesterComment != "lost laptop" || recoverRequest.Contact != "john@example.org" {
+		t.Errorf("comment and contact were not sent, got %+v", recoverRequest)
+	}
+}
@@ -0,0 +1,202 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Export
+
+type ExportFormat string
+
+const (
+	CSV        ExportFormat = "csv"
+	JSONLines  ExportFormat = "jsonl"
+	exportPage              = 100
+)
+
+// DefaultExportColumns are the columns of a CSV export when neither the options nor the query specify fields
+var DefaultExportColumns = []string{"_id", "module", "workflow", "status", "profile", "dn", "requester", "approver", "registrationDate", "lastModificationDate"}
+
+// dateFields are the fields holding epoch milliseconds, exported as RFC 3339 dates
+var dateFields = map[string]bool{
+	"registrationDate":     true,
+	"lastModificationDate": true,
+	"expirationDate":       true,
+	"removeAt":             true,
+}
+
+type ExportOptions struct {
+	Format ExportFormat
+	// Columns of a CSV export, named after the search fields. Defaults to the fields of the query, then to DefaultExportColumns
+	Columns []string
+	// PageSize of the searches, defaults to 100
+	PageSize int
+}
+
+// Export pages through the requests matching the query and writes them to w as CSV (with a header line) or JSON Lines.
+// Each page is written as soon as it is received. Dates are converted from epoch milliseconds to RFC 3339.
+// Pages are fetched by keyset on the modification date like the watcher, so the sort of the query is replaced and the _id and
+// lastModificationDate fields are always requested. A request modified during the export is only exported once.
+// It returns the number of requests exported.
+func (c *Client) Export(query horizon.RequestSearchQuery, w io.Writer, options ExportOptions) (int, error) {
+	if options.PageSize <= 0 {
+		options.PageSize = exportPage
+	}
+	var csvWriter *csv.Writer
+	columns := options.Columns
+	switch options.Format {
+	case CSV:
+		if len(columns) == 0 {
+			columns = query.Fields
+		}
+		if len(columns) == 0 {
+			columns = DefaultExportColumns
+		}
+		if len(query.Fields) == 0 {
+			query.Fields = columns
+		}
+		csvWriter = csv.NewWriter(w)
+		if err := csvWriter.Write(columns); err != nil {
+			return 0, err
+		}
+	case JSONLines:
+	default:
+		return 0, fmt.Errorf("unsupported export format '%s'", options.Format)
+	}
+	if len(query.Fields) > 0 {
+		query.Fields = withKeysetFields(query.Fields)
+	}
+	query.SortedBy = []horizon.SortFields{{Element: "lastModificationDate", Order: horizon.Ascendant}, {Element: "_id", Order: horizon.Ascendant}}
+	query.PageSize = options.PageSize
+	filter := query.Query
+
+	count := 0
+	exported := make(map[string]bool)
+	// The keyset is the last modification date exported and the requests already exported at that date
+	var keysetDate int64
+	keysetSeen := make(map[string]bool)
+	for page := 1; ; {
+		from := keysetDate
+		if from > 0 {
+			query.Query = modifiedSinceQuery(from, filter)
+		}
+		query.PageIndex = page
+		results, err := c.Search(query)
+		if err != nil {
+			return count, err
+		}
+		for _, result := range results.Results {
+			if result.LastModificationDate < keysetDate || (result.LastModificationDate == keysetDate && keysetSeen[result.Id]) {
+				continue
+			}
+			if result.LastModificationDate > keysetDate {
+				keysetDate = result.LastModificationDate
+				keysetSeen = make(map[string]bool)
+			}
+			keysetSeen[result.Id] = true
+			if exported[result.Id] {
+				continue
+			}
+			exported[result.Id] = true
+			fields, err := exportFields(result)
+			if err != nil {
+				return count, err
+			}
+			if csvWriter != nil {
+				record := make([]string, len(columns))
+				for i, column := range columns {
+					record[i] = csvValue(fields[column])
+				}
+				err = csvWriter.Write(record)
+			} else {
+				var line []byte
+				line, err = json.Marshal(fields)
+				if err == nil {
+					_, err = w.Write(append(line, '\n'))
+				}
+			}
+			if err != nil {
+				return count, err
+			}
+			count++
+		}
+		if csvWriter != nil {
+			csvWriter.Flush()
+			if err := csvWriter.Error(); err != nil {
+				return count, err
+			}
+		}
+		if !results.HasMore || len(results.Results) == 0 {
+			return count, nil
+		}
+		if keysetDate == from {
+			// The whole page was modified at the same date, the next one may hold more requests of that date
+			page++
+		} else {
+			page = 1
+		}
+	}
+}
+
+// withKeysetFields returns the fields completed with the ones the export pages are keyed on
+func withKeysetFields(fields []string) []string {
+	result := append([]string(nil), fields...)
+	for _, keysetField := range []string{"_id", "lastModificationDate"} {
+		found := false
+		for _, field := range fields {
+			found = found || field == keysetField
+		}
+		if !found {
+			result = append(result, keysetField)
+		}
+	}
+	return result
+}
+
+// exportFields returns the fields of a request as they are named in the search API, with dates converted to RFC 3339
+func exportFields(result horizon.RequestSearchResult) (map[string]interface{}, error) {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var fields map[string]interface{}
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	for field := range dateFields {
+		number, ok := fields[field].(json.Number)
+		if !ok {
+			continue
+		}
+		millis, err := number.Int64()
+		if err != nil || millis == 0 {
+			delete(fields, field)
+			continue
+		}
+		fields[field] = time.UnixMilli(millis).UTC().Format(time.RFC3339)
+	}
+	return fields, nil
+}
+
+// csvValue formats a field in a CSV cell, structured values (labels, metadata...) being written as JSON
+func csvValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number, bool:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(value)
+	return string(data)
+}
@@ -0,0 +1,126 @@
+package requests
+
+import (
+	"bytes"
+	"encoding/json"
+	gohttp "net/http"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// newPagedSearchClient returns a client backed by a fake Horizon answering every search with the page returned by the function
+func newPagedSearchClient(t *testing.T, search func(query horizon.RequestSearchQuery) horizon.SearchResults[horizon.RequestSearchResult]) *Client {
+	return newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var query horizon.RequestSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(search(query))
+	})
+}
+
+func TestExport(t *testing.T) {
+	var fields [][]string
+	client := newPagedSearchClient(t, func(query horizon.RequestSearchQuery) horizon.SearchResults[horizon.RequestSearchResult] {
+		fields = append(fields, query.Fields)
+		// Two pages of one request each
+		result := horizon.RequestSearchResult{
+			Id:               "request-" + string(rune('0'+query.PageIndex)),
+			Workflow:         horizon.Enroll,
+			Requester:        "john, doe",
+			RegistrationDate: 1700000000000,
+			Labels:           []horizon.Label{{Key: "env", Value: "prod"}},
+		}
+		return horizon.SearchResults[horizon.RequestSearchResult]{Results: []horizon.RequestSearchResult{result}, HasMore: query.PageIndex < 2}
+	})
+
+	var out bytes.Buffer
+	count, err := client.Export(horizon.RequestSearchQuery{Query: "status is completed"}, &out, ExportOptions{
+		Format:  CSV,
+		Columns: []string{"_id", "requester", "registrationDate", "approver", "labels"},
+	})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expected := "_id,requester,registrationDate,approver,labels\n" +
+		`request-1,"john, doe",2023-11-14T22:13:20Z,,"[{""key"":""env"",""value"":""prod""}]"` + "\n" +
+		`request-2,"john, doe",2023-11-14T22:13:20Z,,"[{""key"":""env"",""value"":""prod""}]"` + "\n"
+	if count != 2 || out.String() != expected {
+		t.Errorf("unexpected CSV export (%d requests):\n%s", count, out.String())
+	}
+	if len(fields[0]) != 6 || fields[0][5] != "lastModificationDate" {
+		t.Errorf("only the exported columns and the keyset should be requested, got %v", fields[0])
+	}
+
+	out.Reset()
+	count, err = client.Export(horizon.RequestSearchQuery{}, &out, ExportOptions{Format: JSONLines})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if count != 2 || len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	var line map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &line); err != nil {
+		t.Fatal(err.Error())
+	}
+	if line["_id"] != "request-2" || line["registrationDate"] != "2023-11-14T22:13:20Z" || line["expirationDate"] != nil {
+		t.Errorf("unexpected JSON line %s", lines[1])
+	}
+}
+
+func TestExportKeysetPaging(t *testing.T) {
+	var mutex sync.Mutex
+	current := []horizon.RequestSearchResult{
+		{Id: "a", Status: horizon.Pending, LastModificationDate: 1100},
+		{Id: "b", Status: horizon.Pending, LastModificationDate: 1200},
+		{Id: "c", Status: horizon.Pending, LastModificationDate: 1200},
+		{Id: "d", Status: horizon.Pending, LastModificationDate: 1300},
+		{Id: "e", Status: horizon.Pending, LastModificationDate: 1400},
+	}
+	var sorts [][]horizon.SortFields
+	client := newSearchClient(t, func(query horizon.RequestSearchQuery) []horizon.RequestSearchResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var after int64
+		if parts := strings.Split(query.Query, `"`); len(parts) > 1 {
+			date, _ := time.Parse("2006-01-02T15:04:05.000Z07:00", parts[1])
+			after = date.UnixMilli()
+		}
+		var matching []horizon.RequestSearchResult
+		for _, request := range current {
+			if request.LastModificationDate > after {
+				matching = append(matching, request)
+			}
+		}
+		sort.SliceStable(matching, func(i, j int) bool {
+			if matching[i].LastModificationDate != matching[j].LastModificationDate {
+				return matching[i].LastModificationDate < matching[j].LastModificationDate
+			}
+			return matching[i].Id < matching[j].Id
+		})
+		sorts = append(sorts, query.SortedBy)
+		if len(sorts) == 1 {
+			// a is approved once the first page was served, moving it to the end of the results
+			current[0].Status = horizon.Approved
+			current[0].LastModificationDate = 1500
+		}
+		return matching
+	})
+	var out bytes.Buffer
+	count, err := client.Export(horizon.RequestSearchQuery{}, &out, ExportOptions{Format: CSV, Columns: []string{"_id"}, PageSize: 2})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if count != 5 || out.String() != "_id\na\nb\nc\nd\ne\n" {
+		t.Errorf("every request should be exported once, got %d requests:\n%s", count, out.String())
+	}
+	if len(sorts[0]) != 2 || sorts[0][1].Element != "_id" {
+		t.Errorf("the requests should be sorted on a stable key, got %v", sorts[0])
+	}
+}
@@ -0,0 +1,176 @@
+package requests
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Import from files
+
+type ImportOptions struct {
+	Profile string
+	// Template is applied to every imported certificate, its private key being set to the key paired with the certificate, if any
+	Template *horizon.WebRAImportTemplate
+	// Passwords are tried in order to decrypt PKCS#12 files
+	Passwords []string
+	// IncludePrivateKeys sends the private keys paired with the certificates to Horizon, to be escrowed. Keys are not sent by default.
+	IncludePrivateKeys bool
+	// RequesterComment is the justification displayed to approvers
+	RequesterComment string
+	Contact          string
+	Labels           []horizon.Label
+	// Concurrency and Checkpoint behave as in SubmitBulk. Imported certificates are identified by their SHA-256 thumbprint
+	Concurrency int
+	Checkpoint  Checkpoint
+}
+
+// ImportedCertificate is the outcome of the import of a leaf certificate.
+// A certificate found in several files is imported once and reported in the first file it was found in.
+type ImportedCertificate struct {
+	Certificate *x509.Certificate
+	// HasPrivateKey is set if the private key was sent along with the certificate
+	HasPrivateKey bool
+	Result        BulkResult
+}
+
+// ImportFileReport lists the certificates imported from a file. Err is set if the file could not be read or parsed.
+type ImportFileReport struct {
+	Path         string
+	Certificates []ImportedCertificate
+	Err          error
+}
+
+// ImportFiles reads certificates and private keys from files or directories (PEM bundles, DER and PKCS#12 files),
+// pairs the leaf certificates with their private keys and submits an import request for each of them.
+// Keys are paired with certificates found in any file, so that a key stored next to its certificate is imported along with it.
+func (c *Client) ImportFiles(ctx context.Context, paths []string, options ImportOptions) []ImportFileReport {
+	var reports []ImportFileReport
+	var files []*horizon.CertificateFile
+	for _, root := range paths {
+		// Errors are reported per file, WalkDir never fails
+		_ = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				reports = append(reports, ImportFileReport{Path: path, Err: err})
+				files = append(files, &horizon.CertificateFile{})
+				return nil
+			}
+			if entry.IsDir() {
+				return nil
+			}
+			file, err := horizon.ReadCertificateFile(path, options.Passwords)
+			if err != nil {
+				file = &horizon.CertificateFile{}
+			}
+			reports = append(reports, ImportFileReport{Path: path, Err: err})
+			files = append(files, file)
+			return nil
+		})
+	}
+
+	var keys []crypto.PrivateKey
+	for _, file := range files {
+		keys = append(keys, file.PrivateKeys...)
+	}
+
+	var items []BulkItem
+	type location struct{ report, certificate int }
+	var locations []location
+	seen := make(map[string]bool)
+	for i, file := range files {
+		for _, certificate := range file.Certificates {
+			if certificate.IsCA {
+				continue
+			}
+			thumbprint := sha256.Sum256(certificate.Raw)
+			itemKey := hex.EncodeToString(thumbprint[:])
+			if seen[itemKey] {
+				continue
+			}
+			seen[itemKey] = true
+			imported := ImportedCertificate{Certificate: certificate}
+			params, err := options.importParams(certificate, pairKey(certificate, keys))
+			if err != nil {
+				imported.Result = BulkResult{Key: itemKey, Err: err}
+				reports[i].Certificates = append(reports[i].Certificates, imported)
+				continue
+			}
+			imported.HasPrivateKey = params.Template != nil && params.Template.PrivateKey != ""
+			reports[i].Certificates = append(reports[i].Certificates, imported)
+			items = append(items, ImportItem(itemKey, params))
+			locations = append(locations, location{i, len(reports[i].Certificates) - 1})
+		}
+	}
+
+	results := c.SubmitBulkSlice(ctx, items, BulkOptions{Concurrency: options.Concurrency, Checkpoint: options.Checkpoint})
+	for i, result := range results {
+		reports[locations[i].report].Certificates[locations[i].certificate].Result = result
+	}
+	return reports
+}
+
+func (o *ImportOptions) importParams(certificate *x509.Certificate, key crypto.PrivateKey) (horizon.WebRAImportRequestParams, error) {
+	params := horizon.WebRAImportRequestParams{
+		CertificatePEM:   string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})),
+		Profile:          o.Profile,
+		RequesterComment: o.RequesterComment,
+		Contact:          o.Contact,
+		Labels:           append([]horizon.Label(nil), o.Labels...),
+	}
+	if o.Template != nil {
+		// Requests are submitted concurrently and decoded into their template, which must not be shared
+		template, err := copyTemplate(o.Template)
+		if err != nil {
+			return params, err
+		}
+		params.Template = template
+	}
+	if key == nil || !o.IncludePrivateKeys {
+		return params, nil
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return params, fmt.Errorf("could not marshal private key: %s", err.Error())
+	}
+	if params.Template == nil {
+		params.Template = &horizon.WebRAImportTemplate{}
+	}
+	params.Template.PrivateKey = string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	return params, nil
+}
+
+// copyTemplate returns a deep copy of the template, unknown fields included
+func copyTemplate(template *horizon.WebRAImportTemplate) (*horizon.WebRAImportTemplate, error) {
+	data, err := json.Marshal(template)
+	if err != nil {
+		return nil, err
+	}
+	var copied horizon.WebRAImportTemplate
+	if err := json.Unmarshal(data, &copied); err != nil {
+		return nil, err
+	}
+	return &copied, nil
+}
+
+// pairKey returns the private key matching the public key of the certificate, if any
+func pairKey(certificate *x509.Certificate, keys []crypto.PrivateKey) crypto.PrivateKey {
+	for _, key := range keys {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			continue
+		}
+		if public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); ok && public.Equal(certificate.PublicKey) {
+			return key
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package requests
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func issueTestCertificate(t *testing.T, cn string, isCA bool) (*ecdsa.PrivateKey, *x509.Certificate) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	certificate, _ := x509.ParseCertificate(der)
+	return key, certificate
+}
+
+func TestImportFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	certificatePem := func(certificate *x509.Certificate) []byte {
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw})
+	}
+
+	// A PEM certificate with its key in a separate file
+	key, leaf := issueTestCertificate(t, "pem", false)
+	write("leaf.pem", certificatePem(leaf))
+	der, _ := x509.MarshalECPrivateKey(key)
+	write("leaf.key", pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}))
+	// A bundle with a leaf and a CA, the CA is not imported
+	_, bundled := issueTestCertificate(t, "bundle", false)
+	_, ca := issueTestCertificate(t, "ca", true)
+	write("bundle.pem", append(certificatePem(bundled), certificatePem(ca)...))
+	// A DER certificate, also present in the bundle
+	write("bundled.der", bundled.Raw)
+	// A PKCS#12 file
+	p12Key, p12Leaf := issueTestCertificate(t, "p12", false)
+	p12, _ := pkcs12.Modern.Encode(p12Key, p12Leaf, nil, "secret")
+	write("leaf.p12", p12)
+	write("notes.txt", []byte("not a certificate"))
+	// A bundle whose second certificate is invalid, the first one is not imported either
+	_, partial := issueTestCertificate(t, "partial", false)
+	write("partial.pem", append(certificatePem(partial), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("invalid")})...))
+
+	client, _ := newMockClient(t)
+	reports := client.ImportFiles(context.Background(), []string{dir, filepath.Join(dir, "missing")}, ImportOptions{
+		Profile:            "import",
+		Passwords:          []string{"wrong", "secret"},
+		Template:           &horizon.WebRAImportTemplate{Owner: &horizon.OwnerElement{Value: &horizon.String{String: "john"}}},
+		Concurrency:        2,
+		IncludePrivateKeys: true,
+	})
+
+	byPath := make(map[string]ImportFileReport)
+	for _, report := range reports {
+		byPath[filepath.Base(report.Path)] = report
+	}
+	if len(byPath) != 8 {
+		t.Fatalf("expected a report per file, got %d", len(byPath))
+	}
+	if byPath["notes.txt"].Err == nil || byPath["missing"].Err == nil {
+		t.Error("unreadable files should be reported")
+	}
+	if byPath["partial.pem"].Err == nil || len(byPath["partial.pem"].Certificates) != 0 {
+		t.Error("a file that cannot be fully parsed should not be imported")
+	}
+	if len(byPath["bundle.pem"].Certificates) != 1 || len(byPath["bundled.der"].Certificates) != 0 {
+		t.Error("CA certificates and duplicates should not be imported")
+	}
+	for _, name := range []string{"leaf.pem", "leaf.p12"} {
+		certificates := byPath[name].Certificates
+		if len(certificates) != 1 || !certificates[0].HasPrivateKey {
+			t.Fatalf("%s should be imported with its private key", name)
+		}
+		result := certificates[0].Result
+		if result.Err != nil || result.RequestId == "" {
+			t.Errorf("%s should be submitted: %v", name, result.Err)
+		}
+		request := result.Request.(*horizon.WebRAImportRequest)
+		if request.Profile != "import" || request.Template.PrivateKey == "" || request.Template.Owner == nil {
+			t.Errorf("%s was not submitted with the expected template", name)
+		}
+	}
+
+	// Private keys are only sent on request
+	reports = client.ImportFiles(context.Background(), []string{filepath.Join(dir, "leaf.p12")}, ImportOptions{Profile: "import", Passwords: []string{"secret"}})
+	imported := reports[0].Certificates[0]
+	if imported.HasPrivateKey || imported.Result.Err != nil || imported.Result.Request.(*horizon.WebRAImportRequest).Template != nil {
+		t.Error("the private key should not be sent by default")
+	}
+}
@@ -0,0 +1,222 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/certificateprofiles"
+)
+
+// Holder quotas
+
+var HolderQuotaExceededError = errors.New("holder quota exceeded")
+
+type QuotaPolicy string
+
+const (
+	// RefuseOnQuota fails the pre-flight when the holder has reached the limit
+	RefuseOnQuota QuotaPolicy = "refuse"
+	// ReplaceOldest revokes the oldest active certificates of the holder to make room for a new enrollment
+	ReplaceOldest QuotaPolicy = "replace"
+)
+
+type HolderQuotaParams struct {
+	HolderId string
+	Profile  string
+	// Limit overrides the maximum number of active certificates and pending enroll requests of the holder on the profile.
+	// It defaults to the ProfileHolderIdCount of the profile, 0 meaning no limit.
+	Limit  int
+	Policy QuotaPolicy
+	// RevocationReason of the certificates revoked by ReplaceOldest, defaults to Superseded
+	RevocationReason horizon.RevocationReason
+	// RequesterComment is the justification displayed to approvers of the revocations
+	RequesterComment string
+}
+
+// HolderUsage is what a holder already owns on a profile.
+// ActiveCertificates are sorted from the oldest to the most recent.
+type HolderUsage struct {
+	HolderId string
+	Profile  string
+	// Limit is the ProfileHolderIdCount of the profile, or the limit given to CheckHolderQuota
+	Limit              int
+	ActiveCertificates []horizon.CertificateSearchResult
+	PendingRequests    []horizon.RequestSearchResult
+	// PendingRevokeRequests are the revocations of active certificates that were already submitted and wait for an approval
+	PendingRevokeRequests []horizon.RequestSearchResult
+	// GlobalLimit is the GlobalHolderIdCount of the profile, GlobalCertificates counting the active certificates of the holder on every profile
+	GlobalLimit        int
+	GlobalCertificates int
+	// Revoked lists the revocations submitted by ReplaceOldest
+	Revoked []*horizon.WebRARevokeRequest
+}
+
+// Total counts the active certificates and pending requests, the certificates whose revocation is completed excluded
+func (u *HolderUsage) Total() int {
+	return len(u.ActiveCertificates) + len(u.PendingRequests) - u.completedRevocations()
+}
+
+// GlobalTotal counts the active certificates on every profile and the pending requests, the certificates whose revocation is completed excluded
+func (u *HolderUsage) GlobalTotal() int {
+	return u.GlobalCertificates + len(u.PendingRequests) - u.completedRevocations()
+}
+
+func (u *HolderUsage) completedRevocations() int {
+	return len(u.Revoked) - len(u.PendingRevocations())
+}
+
+// PendingRevocations returns the revocations submitted by ReplaceOldest that are not completed yet, e.g. waiting for an approval
+func (u *HolderUsage) PendingRevocations() []*horizon.WebRARevokeRequest {
+	var pending []*horizon.WebRARevokeRequest
+	for _, revocation := range u.Revoked {
+		if revocation.Status != horizon.Completed {
+			pending = append(pending, revocation)
+		}
+	}
+	return pending
+}
+
+// CanEnroll returns true if a new enrollment stays within the limits
+func (u *HolderUsage) CanEnroll() bool {
+	return u.excess() <= 0
+}
+
+// excess returns the number of certificates to revoke for a new enrollment to stay within the limits
+func (u *HolderUsage) excess() int {
+	excess := 0
+	if u.Limit > 0 {
+		excess = u.Total() - u.Limit + 1
+	}
+	if u.GlobalLimit > 0 && u.GlobalTotal()-u.GlobalLimit+1 > excess {
+		excess = u.GlobalTotal() - u.GlobalLimit + 1
+	}
+	return excess
+}
+
+// GetHolderUsage reports the active certificates and pending requests of a holder on a profile, and the limits set by the profile
+func (c *Client) GetHolderUsage(holderId string, profile string) (*HolderUsage, error) {
+	certificateProfile, err := (&certificateprofiles.Client{Http: c.http}).Get(profile)
+	if err != nil {
+		return nil, err
+	}
+	usage := HolderUsage{HolderId: holderId, Profile: profile, Limit: certificateProfile.ProfileHolderIdCount, GlobalLimit: certificateProfile.GlobalHolderIdCount}
+	holderFilter := fmt.Sprintf("holderId equals %s", strconv.Quote(holderId))
+	filter := fmt.Sprintf("%s and profile equals %s", holderFilter, strconv.Quote(profile))
+	for page := 1; ; page++ {
+		jsonData, _ := json.Marshal(horizon.CertificateSearchQuery{Query: filter + " and status is valid", PageIndex: page})
+		response, err := c.http.Post("/api/v1/certificates/search", jsonData)
+		if err != nil {
+			return nil, err
+		}
+		var results horizon.SearchResults[horizon.CertificateSearchResult]
+		if err := response.Json().Decode(&results); err != nil {
+			return nil, err
+		}
+		usage.ActiveCertificates = append(usage.ActiveCertificates, results.Results...)
+		if !results.HasMore || len(results.Results) == 0 {
+			break
+		}
+	}
+	if usage.GlobalLimit > 0 {
+		jsonData, _ := json.Marshal(horizon.CertificateSearchQuery{Query: holderFilter + " and status is valid", Fields: []string{"_id"}, PageSize: 1, WithCount: true})
+		response, err := c.http.Post("/api/v1/certificates/search", jsonData)
+		if err != nil {
+			return nil, err
+		}
+		var results horizon.SearchResults[horizon.CertificateSearchResult]
+		if err := response.Json().Decode(&results); err != nil {
+			return nil, err
+		}
+		usage.GlobalCertificates = results.Count
+	}
+	if usage.PendingRequests, err = c.searchAll(filter + " and workflow is enroll and status is pending"); err != nil {
+		return nil, err
+	}
+	if usage.PendingRevokeRequests, err = c.searchAll(filter + " and workflow is revoke and status is pending"); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(usage.ActiveCertificates, func(i, j int) bool {
+		return usage.ActiveCertificates[i].NotBefore < usage.ActiveCertificates[j].NotBefore
+	})
+	return &usage, nil
+}
+
+// searchAll pages through the requests matching the query
+func (c *Client) searchAll(query string) ([]horizon.RequestSearchResult, error) {
+	var requests []horizon.RequestSearchResult
+	for page := 1; ; page++ {
+		results, err := c.Search(horizon.RequestSearchQuery{Query: query, PageIndex: page})
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, results.Results...)
+		if !results.HasMore || len(results.Results) == 0 {
+			return requests, nil
+		}
+	}
+}
+
+// CheckHolderQuota is a pre-flight to run before NewEnrollRequest.
+// If the holder has reached a limit of the profile, RefuseOnQuota returns HolderQuotaExceededError while ReplaceOldest submits
+// revocations for the oldest active certificates on the profile until a new enrollment fits. Pending requests are never canceled:
+// nothing is revoked if they alone reach the limit. Certificates whose revocation was already submitted are not revoked again,
+// and a revocation waiting for an approval does not make room yet: HolderQuotaExceededError is returned until it completes.
+func (c *Client) CheckHolderQuota(params HolderQuotaParams) (*HolderUsage, error) {
+	usage, err := c.GetHolderUsage(params.HolderId, params.Profile)
+	if err != nil {
+		return nil, err
+	}
+	if params.Limit > 0 {
+		usage.Limit = params.Limit
+	}
+	if usage.CanEnroll() {
+		return usage, nil
+	}
+	if params.Policy != ReplaceOldest {
+		return usage, fmt.Errorf("%w: %s has %d certificates and requests on profile %s (limit %d, %d on every profile with limit %d)", HolderQuotaExceededError, params.HolderId, usage.Total(), params.Profile, usage.Limit, usage.GlobalTotal(), usage.GlobalLimit)
+	}
+	if (usage.Limit > 0 && len(usage.PendingRequests) >= usage.Limit) || (usage.GlobalLimit > 0 && len(usage.PendingRequests) >= usage.GlobalLimit) {
+		return usage, fmt.Errorf("%w: %s has %d pending requests on profile %s", HolderQuotaExceededError, params.HolderId, len(usage.PendingRequests), params.Profile)
+	}
+	reason := params.RevocationReason
+	if reason == "" {
+		reason = horizon.Superseded
+	}
+	pendingRevocation := make(map[string]bool)
+	for _, request := range usage.PendingRevokeRequests {
+		pendingRevocation[request.CertificateId] = true
+	}
+	var candidates []horizon.CertificateSearchResult
+	for _, certificate := range usage.ActiveCertificates {
+		if !pendingRevocation[certificate.Id] {
+			candidates = append(candidates, certificate)
+		}
+	}
+	// The certificates whose revocation is pending will make room once approved
+	excess := usage.excess() - len(pendingRevocation)
+	if excess > len(candidates) {
+		return usage, fmt.Errorf("%w: %s has %d certificates on every profile (limit %d), too many to make room on profile %s", HolderQuotaExceededError, params.HolderId, usage.GlobalTotal(), usage.GlobalLimit, params.Profile)
+	}
+	if excess < 0 {
+		excess = 0
+	}
+	for _, certificate := range candidates[:excess] {
+		revocation, err := c.NewRevokeRequest(horizon.WebRARevokeRequestParams{
+			CertificateId:    certificate.Id,
+			RevocationReason: reason,
+			RequesterComment: params.RequesterComment,
+		})
+		if err != nil {
+			return usage, err
+		}
+		usage.Revoked = append(usage.Revoked, revocation)
+	}
+	if !usage.CanEnroll() {
+		return usage, fmt.Errorf("%w: %d revocations of the certificates of %s are pending", HolderQuotaExceededError, len(usage.PendingRevocations())+len(usage.PendingRevokeRequests), params.HolderId)
+	}
+	return usage, nil
+}
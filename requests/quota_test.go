@@ -0,0 +1,105 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	gohttp "net/http"
+	"strings"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestCheckHolderQuota(t *testing.T) {
+	var queries []string
+	revocationStatus := horizon.Completed
+	profileLimit, globalLimit := 3, 0
+	var pendingRevokes []horizon.RequestSearchResult
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var body map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/v1/certificate/profiles/iot":
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"_id": "iot", "profileHolderIdCount": profileLimit, "globalHolderIdCount": globalLimit})
+		case "/api/v1/certificates/search":
+			queries = append(queries, body["query"].(string))
+			if body["withCount"] == true {
+				// The holder has 3 more certificates on other profiles
+				_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Count: 6})
+				return
+			}
+			_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.CertificateSearchResult]{Results: []horizon.CertificateSearchResult{
+				{Id: "recent", NotBefore: 3000}, {Id: "oldest", NotBefore: 1000}, {Id: "old", NotBefore: 2000},
+			}})
+		case "/api/v1/requests/search":
+			queries = append(queries, body["query"].(string))
+			results := []horizon.RequestSearchResult{{Id: "pending"}}
+			if strings.Contains(body["query"].(string), "workflow is revoke") {
+				results = pendingRevokes
+			}
+			_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.RequestSearchResult]{Results: results})
+		default:
+			body["_id"] = "revocation"
+			body["status"] = revocationStatus
+			_ = json.NewEncoder(w).Encode(body)
+		}
+	})
+
+	// The limit is read from the profile
+	usage, err := client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot"})
+	if !errors.Is(err, HolderQuotaExceededError) || usage.Total() != 4 || usage.Limit != 3 {
+		t.Errorf("quota should be exceeded, got %v", err)
+	}
+	if !strings.Contains(queries[0], `holderId equals "device" and profile equals "iot"`) {
+		t.Errorf("unexpected query %s", queries[0])
+	}
+	if usage.ActiveCertificates[0].Id != "oldest" {
+		t.Error("active certificates should be sorted from the oldest")
+	}
+
+	usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Policy: ReplaceOldest})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(usage.Revoked) != 2 || usage.Revoked[0].CertificateId != "oldest" || usage.Revoked[1].CertificateId != "old" {
+		t.Errorf("the two oldest certificates should be revoked, got %d revocations", len(usage.Revoked))
+	}
+	if usage.Revoked[0].Template.RevocationReason != horizon.Superseded || !usage.CanEnroll() {
+		t.Error("revocations should be superseded and make room for an enrollment")
+	}
+
+	usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Limit: 1, Policy: ReplaceOldest})
+	if !errors.Is(err, HolderQuotaExceededError) || len(usage.Revoked) != 0 {
+		t.Error("pending requests cannot be replaced, nothing should be revoked")
+	}
+
+	// Revocations waiting for an approval do not make room yet
+	revocationStatus = horizon.Pending
+	usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Policy: ReplaceOldest})
+	if !errors.Is(err, HolderQuotaExceededError) || len(usage.Revoked) != 2 || len(usage.PendingRevocations()) != 2 || usage.Total() != 4 {
+		t.Errorf("pending revocations should not be counted, got %v", err)
+	}
+
+	// Certificates whose revocation was already submitted are not revoked again
+	pendingRevokes = []horizon.RequestSearchResult{{Id: "revocation", Workflow: horizon.Revoke, CertificateId: "oldest"}}
+	usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Policy: ReplaceOldest})
+	if !errors.Is(err, HolderQuotaExceededError) || len(usage.Revoked) != 1 || usage.Revoked[0].CertificateId != "old" {
+		t.Errorf("only the old certificate should be revoked, got %d revocations and %v", len(usage.Revoked), err)
+	}
+
+	// The global limit counts the certificates of every profile
+	revocationStatus, pendingRevokes = horizon.Completed, nil
+	profileLimit, globalLimit = 0, 5
+	usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Policy: ReplaceOldest})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(usage.Revoked) != 3 || usage.GlobalTotal() != 4 {
+		t.Errorf("the three certificates of the profile should be revoked, got %d revocations", len(usage.Revoked))
+	}
+	globalLimit = 3
+	if usage, err = client.CheckHolderQuota(HolderQuotaParams{HolderId: "device", Profile: "iot", Policy: ReplaceOldest}); !errors.Is(err, HolderQuotaExceededError) || len(usage.Revoked) != 0 {
+		t.Errorf("nothing should be revoked when the profile cannot make enough room, got %v", err)
+	}
+}
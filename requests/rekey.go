@@ -0,0 +1,137 @@
+package requests
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Renewal with a new key
+
+var CentralizedRenewalError = errors.New("the profile does not allow decentralized renewals")
+
+type RenewWithNewKeyParams struct {
+	CertToRenewId  string
+	CertToRenewPem string
+	// KeyType of the new key. Defaults to the key type of the renewed certificate, upgraded if the profile no longer authorizes it
+	KeyType string
+	// RequesterComment is the justification displayed to approvers
+	RequesterComment string
+	Contact          string
+	Labels           []horizon.Label
+}
+
+// RenewedKey is the outcome of a renewal with a new key.
+// The certificate is only available in the request once it has been approved.
+type RenewedKey struct {
+	Request    *horizon.WebRARenewRequest
+	PrivateKey crypto.Signer
+	KeyType    string
+	// Csr is the PEM encoded CSR submitted to Horizon
+	Csr string
+}
+
+// KeyMaterial returns the new private key along with the renewed certificate, once issued
+func (r *RenewedKey) KeyMaterial() (*horizon.KeyMaterial, error) {
+	if r.Request == nil {
+		return nil, errors.New("no renewal request was submitted")
+	}
+	if r.Request.Certificate == nil || r.Request.Certificate.Certificate == "" {
+		return nil, fmt.Errorf("request is %s, no certificate was issued yet", r.Request.GetStatus())
+	}
+	certificate, err := horizon.ParseCertificatePem(r.Request.Certificate.Certificate)
+	if err != nil {
+		return nil, err
+	}
+	return &horizon.KeyMaterial{PrivateKey: r.PrivateKey, Certificate: certificate}, nil
+}
+
+// RenewWithNewKey renews a certificate with a locally generated key.
+// The key is of the same type as the renewed certificate (or upgraded according to the capabilities of the renew template),
+// and the CSR mirrors the subject and SANs of the renewed certificate.
+func (c *Client) RenewWithNewKey(params RenewWithNewKeyParams) (*RenewedKey, error) {
+	certificatePem := params.CertToRenewPem
+	if certificatePem == "" {
+		if params.CertToRenewId == "" {
+			return nil, errors.New("either the ID or the PEM of the certificate to renew is required")
+		}
+		response, err := c.http.Get("/api/v1/certificates/" + params.CertToRenewId)
+		if err != nil {
+			return nil, err
+		}
+		var certificate horizon.CertificateResponse
+		if err := response.Json().Decode(&certificate); err != nil {
+			return nil, err
+		}
+		certificatePem = certificate.Certificate.Certificate
+	}
+	certificate, err := horizon.ParseCertificatePem(certificatePem)
+	if err != nil {
+		return nil, fmt.Errorf("invalid certificate to renew: %s", err.Error())
+	}
+
+	template, err := c.GetRenewTemplate(horizon.WebRARenewTemplateParams{CertificateId: params.CertToRenewId, CertificatePEM: params.CertToRenewPem})
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		template = &horizon.WebRARenewTemplate{}
+	}
+	if template.Capabilities != nil && !template.Capabilities.Decentralized {
+		return nil, CentralizedRenewalError
+	}
+
+	keyType := params.KeyType
+	if keyType == "" {
+		if keyType, err = horizon.KeyTypeOf(certificate.PublicKey); err != nil {
+			return nil, err
+		}
+		if keyType, err = horizon.UpgradeKeyType(keyType, template.Capabilities); err != nil {
+			return nil, err
+		}
+	}
+	key, err := horizon.GenerateKey(keyType)
+	if err != nil {
+		return nil, err
+	}
+	csr, err := mirrorCsr(certificate, key)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Csr = csr
+	template.KeyType = ""
+	request, err := c.NewRenewRequest(horizon.WebRARenewRequestParams{
+		Template:         template,
+		CertToRenewId:    params.CertToRenewId,
+		CertToRenewPem:   params.CertToRenewPem,
+		RequesterComment: params.RequesterComment,
+		Contact:          params.Contact,
+		Labels:           params.Labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &RenewedKey{Request: request, PrivateKey: key, KeyType: keyType, Csr: csr}, nil
+}
+
+// mirrorCsr creates a PEM encoded CSR with the subject and SANs of the certificate, signed by the key
+func mirrorCsr(certificate *x509.Certificate, key crypto.Signer) (string, error) {
+	template := x509.CertificateRequest{
+		RawSubject:     certificate.RawSubject,
+		DNSNames:       certificate.DNSNames,
+		EmailAddresses: certificate.EmailAddresses,
+		IPAddresses:    certificate.IPAddresses,
+		URIs:           certificate.URIs,
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &template, key)
+	if err != nil {
+		return "", fmt.Errorf("could not generate CSR: %s", err.Error())
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})), nil
+}
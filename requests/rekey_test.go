@@ -0,0 +1,67 @@
+package requests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	gohttp "net/http"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+func TestRenewWithNewKey(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.org", Organization: []string{"Evertrust"}},
+		DNSNames:     []string{"example.org", "www.example.org"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, _ := x509.CreateCertificate(rand.Reader, &template, &template, key.Public(), key)
+	certificatePem := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var request map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if r.URL.Path == "/api/v1/requests/template" {
+			request["template"] = map[string]interface{}{
+				"capabilities": map[string]interface{}{"decentralized": true, "authorizedKeyTypes": []string{"rsa-2048", "ec-secp384r1"}},
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(request)
+	})
+
+	renewed, err := client.RenewWithNewKey(RenewWithNewKeyParams{CertToRenewPem: certificatePem, RequesterComment: "rotation"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if renewed.KeyType != "ec-secp384r1" {
+		t.Errorf("ec-secp256r1 should be upgraded to ec-secp384r1, got %s", renewed.KeyType)
+	}
+	block, _ := pem.Decode([]byte(renewed.Request.Template.Csr))
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if csr.Subject.CommonName != "example.org" || len(csr.DNSNames) != 2 {
+		t.Errorf("CSR does not mirror the renewed certificate: %v %v", csr.Subject, csr.DNSNames)
+	}
+	if actual, _ := horizon.KeyTypeOf(csr.PublicKey); actual != "ec-secp384r1" || csr.CheckSignature() != nil {
+		t.Error("CSR should be signed by the new key")
+	}
+	if renewed.Request.CertificatePEM != certificatePem || renewed.Request.RequesterComment != "rotation" {
+		t.Error("renewed certificate and comment should be submitted")
+	}
+	if _, err := renewed.KeyMaterial(); err == nil {
+		t.Error("no key material is expected before the certificate is issued")
+	}
+}
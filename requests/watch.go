@@ -0,0 +1,340 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// Request watcher
+
+type RequestEventType string
+
+const (
+	RequestCreated   RequestEventType = "created"
+	RequestApproved  RequestEventType = "approved"
+	RequestDenied    RequestEventType = "denied"
+	RequestCompleted RequestEventType = "completed"
+	RequestCanceled  RequestEventType = "canceled"
+	RequestExpired   RequestEventType = "expired"
+)
+
+// RequestEvent is a change of state of a request.
+// RequestExpired events are detected locally, as Horizon has no expired status and does not report a modification when a request expires:
+// their Request is nil and their Status is the last known one.
+type RequestEvent struct {
+	Type           RequestEventType
+	RequestId      string
+	Status         horizon.Status
+	PreviousStatus horizon.Status
+	Request        *horizon.RequestSearchResult
+}
+
+// TrackedRequest is the last known state of a request that has not reached a final status yet
+type TrackedRequest struct {
+	Status         horizon.Status `json:"status"`
+	ExpirationDate int64          `json:"expirationDate,omitempty"`
+}
+
+// WatchCursor is the state of a watcher, persisted between polls so that it can be restarted without missing or repeating events
+type WatchCursor struct {
+	// LastModificationDate is the most recent modification date seen, in epoch milliseconds
+	LastModificationDate int64 `json:"lastModificationDate"`
+	// SeenAtLastModificationDate lists the requests already processed whose modification date is LastModificationDate
+	SeenAtLastModificationDate []string                  `json:"seenAtLastModificationDate,omitempty"`
+	Tracked                    map[string]TrackedRequest `json:"tracked,omitempty"`
+}
+
+// CursorStore persists the cursor of a watcher. Load returns a nil cursor if none was saved yet.
+type CursorStore interface {
+	Load() (*WatchCursor, error)
+	Save(cursor *WatchCursor) error
+}
+
+type MemoryCursorStore struct {
+	mutex  sync.Mutex
+	cursor *WatchCursor
+}
+
+func (m *MemoryCursorStore) Load() (*WatchCursor, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	return m.cursor, nil
+}
+
+func (m *MemoryCursorStore) Save(cursor *WatchCursor) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.cursor = cursor
+	return nil
+}
+
+// FileCursorStore persists the cursor as a JSON file, replaced atomically on each save
+type FileCursorStore struct {
+	Path string
+}
+
+func (f *FileCursorStore) Load() (*WatchCursor, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var cursor WatchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("invalid cursor file %s: %s", f.Path, err.Error())
+	}
+	return &cursor, nil
+}
+
+func (f *FileCursorStore) Save(cursor *WatchCursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
+
+type WatchOptions struct {
+	// Query is an optional HPQL filter restricting the watched requests
+	Query string
+	Scope horizon.SearchScope
+	// Interval between two polls, defaults to 30 seconds
+	Interval time.Duration
+	// Since is the date from which changes are reported when the store holds no cursor, defaults to now
+	Since time.Time
+	// CursorStore defaults to a MemoryCursorStore
+	CursorStore CursorStore
+	// PageSize of the searches, defaults to 100
+	PageSize int
+}
+
+// RequestWatcher reports the changes of state of the requests. Both channels must be drained, and are closed when the context is canceled.
+type RequestWatcher struct {
+	Events <-chan RequestEvent
+	Errors <-chan error
+}
+
+type watcher struct {
+	client  *Client
+	options WatchOptions
+	cursor  *WatchCursor
+	now     func() time.Time
+}
+
+// Watch periodically searches the requests modified since the last poll and emits an event for each change of status
+func (c *Client) Watch(ctx context.Context, options WatchOptions) (*RequestWatcher, error) {
+	w, err := c.newWatcher(options)
+	if err != nil {
+		return nil, err
+	}
+	events := make(chan RequestEvent)
+	errs := make(chan error)
+	go func() {
+		defer close(events)
+		defer close(errs)
+		ticker := time.NewTicker(w.options.Interval)
+		defer ticker.Stop()
+		for {
+			newEvents, err := w.poll()
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+			}
+			for _, event := range newEvents {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return &RequestWatcher{Events: events, Errors: errs}, nil
+}
+
+func (c *Client) newWatcher(options WatchOptions) (*watcher, error) {
+	if options.Interval <= 0 {
+		options.Interval = 30 * time.Second
+	}
+	if options.PageSize <= 0 {
+		options.PageSize = 100
+	}
+	if options.CursorStore == nil {
+		options.CursorStore = &MemoryCursorStore{}
+	}
+	w := watcher{client: c, options: options, now: time.Now}
+	cursor, err := options.CursorStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	if cursor == nil {
+		since := options.Since
+		if since.IsZero() {
+			since = w.now()
+		}
+		cursor = &WatchCursor{LastModificationDate: since.UnixMilli()}
+	}
+	if cursor.Tracked == nil {
+		cursor.Tracked = make(map[string]TrackedRequest)
+	}
+	w.cursor = cursor
+	return &w, nil
+}
+
+// modifiedSinceQuery selects the requests modified at or after the given epoch milliseconds
+func modifiedSinceQuery(millis int64, filter string) string {
+	query := fmt.Sprintf(`lastModificationDate after "%s"`, time.UnixMilli(millis-1).UTC().Format("2006-01-02T15:04:05.000Z07:00"))
+	if filter != "" {
+		query = "(" + filter + ") and " + query
+	}
+	return query
+}
+
+func isFinalStatus(status horizon.Status) bool {
+	switch status {
+	case horizon.Denied, horizon.Canceled, horizon.Completed:
+		return true
+	}
+	return false
+}
+
+func statusEventType(status horizon.Status) (RequestEventType, bool) {
+	switch status {
+	case horizon.Approved:
+		return RequestApproved, true
+	case horizon.Denied:
+		return RequestDenied, true
+	case horizon.Completed:
+		return RequestCompleted, true
+	case horizon.Canceled:
+		return RequestCanceled, true
+	}
+	return "", false
+}
+
+// poll fetches the requests modified since the cursor, computes the events and saves the new cursor.
+// The changes are applied to a copy of the cursor, which only replaces the current one once all the pages were fetched and saved,
+// so that a failed poll is retried from the same state.
+// Pages are fetched by keyset: each search restarts from the last modification date seen, skipping the requests already processed,
+// so that requests modified while polling do not shift the pages. The page index only grows while a page holds nothing newer than its first date.
+func (w *watcher) poll() ([]RequestEvent, error) {
+	var events []RequestEvent
+	seen := make(map[string]bool)
+	for _, id := range w.cursor.SeenAtLastModificationDate {
+		seen[id] = true
+	}
+	cursor := WatchCursor{
+		LastModificationDate:       w.cursor.LastModificationDate,
+		SeenAtLastModificationDate: append([]string(nil), w.cursor.SeenAtLastModificationDate...),
+		Tracked:                    make(map[string]TrackedRequest, len(w.cursor.Tracked)),
+	}
+	for id, tracked := range w.cursor.Tracked {
+		cursor.Tracked[id] = tracked
+	}
+	for page := 1; ; {
+		from := cursor.LastModificationDate
+		results, err := w.client.Search(horizon.RequestSearchQuery{
+			Query:     modifiedSinceQuery(from, w.options.Query),
+			SortedBy:  []horizon.SortFields{{Element: "lastModificationDate", Order: horizon.Ascendant}, {Element: "_id", Order: horizon.Ascendant}},
+			PageIndex: page,
+			PageSize:  w.options.PageSize,
+			Scope:     w.options.Scope,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i := range results.Results {
+			request := results.Results[i]
+			if request.LastModificationDate < cursor.LastModificationDate ||
+				(request.LastModificationDate == cursor.LastModificationDate && seen[request.Id]) {
+				continue
+			}
+			events = append(events, w.diff(&cursor, &request)...)
+			if request.LastModificationDate > cursor.LastModificationDate {
+				cursor.LastModificationDate = request.LastModificationDate
+				cursor.SeenAtLastModificationDate = nil
+				seen = make(map[string]bool)
+			}
+			cursor.SeenAtLastModificationDate = append(cursor.SeenAtLastModificationDate, request.Id)
+			seen[request.Id] = true
+		}
+		if !results.HasMore || len(results.Results) == 0 {
+			break
+		}
+		if cursor.LastModificationDate == from {
+			// The whole page was modified at the same date, the next one may hold more requests of that date
+			page++
+		} else {
+			page = 1
+		}
+	}
+	events = append(events, w.expire(&cursor)...)
+	if err := w.options.CursorStore.Save(&cursor); err != nil {
+		return nil, err
+	}
+	w.cursor = &cursor
+	return events, nil
+}
+
+// diff computes the events of a modified request and updates the tracked requests of the new cursor accordingly
+func (w *watcher) diff(cursor *WatchCursor, request *horizon.RequestSearchResult) []RequestEvent {
+	var events []RequestEvent
+	previous, tracked := cursor.Tracked[request.Id]
+	if !tracked && request.RegistrationDate >= w.cursor.LastModificationDate {
+		events = append(events, RequestEvent{Type: RequestCreated, RequestId: request.Id, Status: request.Status, Request: request})
+	}
+	if !tracked || previous.Status != request.Status {
+		if eventType, ok := statusEventType(request.Status); ok {
+			events = append(events, RequestEvent{Type: eventType, RequestId: request.Id, Status: request.Status, PreviousStatus: previous.Status, Request: request})
+		}
+	}
+	if isFinalStatus(request.Status) {
+		delete(cursor.Tracked, request.Id)
+	} else {
+		cursor.Tracked[request.Id] = TrackedRequest{Status: request.Status, ExpirationDate: request.ExpirationDate}
+	}
+	return events
+}
+
+// expire emits an event for the tracked pending requests whose expiration date has passed
+func (w *watcher) expire(cursor *WatchCursor) []RequestEvent {
+	var events []RequestEvent
+	now := w.now().UnixMilli()
+	for id, tracked := range cursor.Tracked {
+		if tracked.Status == horizon.Pending && tracked.ExpirationDate > 0 && tracked.ExpirationDate <= now {
+			events = append(events, RequestEvent{Type: RequestExpired, RequestId: id, Status: tracked.Status, PreviousStatus: tracked.Status})
+			delete(cursor.Tracked, id)
+		}
+	}
+	return events
+}
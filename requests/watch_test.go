@@ -0,0 +1,225 @@
+package requests
+
+import (
+	"context"
+	"encoding/json"
+	gohttp "net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+)
+
+// newSearchClient returns a client backed by a fake Horizon answering every search with the requested page of the results returned by the function
+func newSearchClient(t *testing.T, results func(query horizon.RequestSearchQuery) []horizon.RequestSearchResult) *Client {
+	return newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		var query horizon.RequestSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		page := horizon.SearchResults[horizon.RequestSearchResult]{Results: results(query)}
+		if query.PageIndex > 0 && query.PageSize > 0 {
+			start := (query.PageIndex - 1) * query.PageSize
+			end := start + query.PageSize
+			if start > len(page.Results) {
+				start = len(page.Results)
+			}
+			if end > len(page.Results) {
+				end = len(page.Results)
+			}
+			page.HasMore = end < len(page.Results)
+			page.Results = page.Results[start:end]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(page)
+	})
+}
+
+func eventTypes(events []RequestEvent) []string {
+	var types []string
+	for _, event := range events {
+		types = append(types, event.RequestId+":"+string(event.Type))
+	}
+	return types
+}
+
+func TestWatcherPoll(t *testing.T) {
+	var mutex sync.Mutex
+	var current []horizon.RequestSearchResult
+	var lastQuery string
+	client := newSearchClient(t, func(query horizon.RequestSearchQuery) []horizon.RequestSearchResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+		lastQuery = query.Query
+		return current
+	})
+	store := &FileCursorStore{Path: filepath.Join(t.TempDir(), "cursor.json")}
+	w, err := client.newWatcher(WatchOptions{Query: "profile is p", Since: time.UnixMilli(1000), CursorStore: store})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	now := int64(1000)
+	w.now = func() time.Time { return time.UnixMilli(now) }
+
+	current = []horizon.RequestSearchResult{
+		{Id: "a", Status: horizon.Pending, RegistrationDate: 1100, LastModificationDate: 1100, ExpirationDate: 5000},
+		{Id: "b", Status: horizon.Completed, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "old", Status: horizon.Denied, RegistrationDate: 500, LastModificationDate: 1200},
+	}
+	events, err := w.poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := strings.Join(eventTypes(events), ","); got != "a:created,b:created,b:completed,old:denied" {
+		t.Errorf("unexpected events %s", got)
+	}
+	if !strings.HasPrefix(lastQuery, "(profile is p) and lastModificationDate after") {
+		t.Errorf("unexpected query %s", lastQuery)
+	}
+
+	// A restarted watcher resumes from the stored cursor and ignores the requests already processed
+	w, err = client.newWatcher(WatchOptions{CursorStore: store})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	w.now = func() time.Time { return time.UnixMilli(now) }
+	current = append(current, horizon.RequestSearchResult{Id: "a", Status: horizon.Approved, RegistrationDate: 1100, LastModificationDate: 1300})
+	events, _ = w.poll()
+	if got := strings.Join(eventTypes(events), ","); got != "a:approved" || events[0].PreviousStatus != horizon.Pending {
+		t.Errorf("unexpected events %s", got)
+	}
+
+	// Pending requests expire without being modified
+	current = []horizon.RequestSearchResult{{Id: "c", Status: horizon.Pending, RegistrationDate: 1400, LastModificationDate: 1400, ExpirationDate: 2000}}
+	w.poll()
+	current = nil
+	now = 3000
+	events, _ = w.poll()
+	if got := strings.Join(eventTypes(events), ","); got != "c:expired" || events[0].Request != nil || events[0].Status != horizon.Pending {
+		t.Errorf("unexpected events %s", got)
+	}
+}
+
+func TestWatcherKeysetPaging(t *testing.T) {
+	var mutex sync.Mutex
+	current := []horizon.RequestSearchResult{
+		{Id: "a", Status: horizon.Pending, RegistrationDate: 1100, LastModificationDate: 1100},
+		{Id: "b", Status: horizon.Pending, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "c", Status: horizon.Pending, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "d", Status: horizon.Pending, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "e", Status: horizon.Pending, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "f", Status: horizon.Pending, RegistrationDate: 1200, LastModificationDate: 1200},
+		{Id: "g", Status: horizon.Pending, RegistrationDate: 1400, LastModificationDate: 1400},
+	}
+	searches := 0
+	client := newSearchClient(t, func(query horizon.RequestSearchQuery) []horizon.RequestSearchResult {
+		mutex.Lock()
+		defer mutex.Unlock()
+		after, _ := time.Parse("2006-01-02T15:04:05.000Z07:00", strings.Split(query.Query, `"`)[1])
+		var matching []horizon.RequestSearchResult
+		for _, request := range current {
+			if request.LastModificationDate > after.UnixMilli() {
+				matching = append(matching, request)
+			}
+		}
+		// Ties are only returned in a stable order when sorted by _id
+		stable := len(query.SortedBy) > 1 && query.SortedBy[1].Element == "_id"
+		sort.SliceStable(matching, func(i, j int) bool {
+			if matching[i].LastModificationDate != matching[j].LastModificationDate {
+				return matching[i].LastModificationDate < matching[j].LastModificationDate
+			}
+			return (matching[i].Id < matching[j].Id) == (stable || searches%2 == 0)
+		})
+		searches++
+		if searches == 1 {
+			// a is approved once the first page was served, moving it to the end of the results
+			current[0].Status = horizon.Approved
+			current[0].LastModificationDate = 1500
+		}
+		return matching
+	})
+	w, err := client.newWatcher(WatchOptions{Since: time.UnixMilli(1000), PageSize: 2})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	events, err := w.poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := strings.Join(eventTypes(events), ","); got != "a:created,b:created,c:created,d:created,e:created,f:created,g:created,a:approved" {
+		t.Errorf("unexpected events %s", got)
+	}
+}
+
+func TestWatcherFailedPoll(t *testing.T) {
+	var mutex sync.Mutex
+	current := []horizon.RequestSearchResult{{Id: "a", Status: horizon.Pending, RegistrationDate: 1100, LastModificationDate: 1100}}
+	failing := false
+	searches := 0
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var query horizon.RequestSearchQuery
+		_ = json.NewDecoder(r.Body).Decode(&query)
+		searches++
+		if failing && searches == 2 {
+			w.WriteHeader(gohttp.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(horizon.SearchResults[horizon.RequestSearchResult]{Results: current, HasMore: failing && searches == 1})
+	})
+	w, err := client.newWatcher(WatchOptions{Since: time.UnixMilli(1000)})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := w.poll(); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// a is approved but the second page cannot be fetched: the poll is retried from the previous state
+	current = []horizon.RequestSearchResult{{Id: "a", Status: horizon.Approved, RegistrationDate: 1100, LastModificationDate: 1200}}
+	failing, searches = true, 0
+	if events, err := w.poll(); err == nil || len(events) != 0 {
+		t.Fatalf("expected an error and no events, got %v, %v", eventTypes(events), err)
+	}
+	if w.cursor.Tracked["a"].Status != horizon.Pending || w.cursor.LastModificationDate != 1100 {
+		t.Errorf("cursor should not be modified by a failed poll, got %+v", w.cursor)
+	}
+	failing = false
+	events, err := w.poll()
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if got := strings.Join(eventTypes(events), ","); got != "a:approved" || events[0].PreviousStatus != horizon.Pending {
+		t.Errorf("unexpected events %s", got)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	client := newSearchClient(t, func(query horizon.RequestSearchQuery) []horizon.RequestSearchResult {
+		return []horizon.RequestSearchResult{{Id: "a", Status: horizon.Completed, RegistrationDate: 1100, LastModificationDate: 1100}}
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	watcher, err := client.Watch(ctx, WatchOptions{Since: time.UnixMilli(1000), Interval: time.Millisecond})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var types []string
+	for len(types) < 2 {
+		select {
+		case event := <-watcher.Events:
+			types = append(types, string(event.Type))
+		case err := <-watcher.Errors:
+			t.Fatal(err.Error())
+		}
+	}
+	cancel()
+	for range watcher.Events {
+	}
+	if strings.Join(types, ",") != "created,completed" {
+		t.Errorf("request should only be reported once, got %v", types)
+	}
+}
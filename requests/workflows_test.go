@@ -0,0 +1,275 @@
+package requests
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	gohttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/http"
+)
+
+// newTestClient returns a client backed by a fake Horizon served by the handler
+func newTestClient(t *testing.T, handler gohttp.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	endpoint, _ := url.Parse(server.URL)
+	baseClient := http.Client{}
+	baseClient.SetHttpClient(nil).SetBaseUrl(*endpoint)
+	return &Client{http: &baseClient}
+}
+
+// newMockClient returns a client backed by a fake Horizon that echoes submitted requests back.
+// Requests fetched by ID are answered with the module and workflow encoded in the ID as "<module>.<workflow>".
+func newMockClient(t *testing.T) (*Client, *[]string) {
+	var calls []string
+	var mutex sync.Mutex
+	return newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		mutex.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		requestId := fmt.Sprintf("request-%d", len(calls))
+		mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/requests/")
+			parts := strings.SplitN(id, ".", 2)
+			_ = json.NewEncoder(w).Encode(map[string]string{"_id": id, "module": parts[0], "workflow": parts[1]})
+			return
+		}
+		// Submitted requests are given an ID, as Horizon would do
+		var request map[string]interface{}
+		_ = json.NewDecoder(r.Body).Decode(&request)
+		if _, ok := request["_id"]; !ok && strings.HasSuffix(r.URL.Path, "/submit") {
+			request["_id"] = requestId
+		}
+		_ = json.NewEncoder(w).Encode(request)
+	}), &calls
+}
+
+type workflowCase struct {
+	name     string
+	call     func(c *Client) (horizon.Request, error)
+	endpoint string
+}
+
+func workflowMatrix() []workflowCase {
+	return []workflowCase{
+		// WebRA
+		{"webra enroll template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetEnrollTemplate(horizon.WebRAEnrollTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra enroll submit", func(c *Client) (horizon.Request, error) {
+			return c.NewEnrollRequest(horizon.WebRAEnrollRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra enroll get", func(c *Client) (horizon.Request, error) { return c.GetEnrollRequest("webra.enroll") }, "GET /api/v1/requests/webra.enroll"},
+		{"webra enroll cancel", func(c *Client) (horizon.Request, error) { return c.CancelEnrollRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra renew template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetRenewTemplate(horizon.WebRARenewTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra renew submit", func(c *Client) (horizon.Request, error) {
+			return c.NewRenewRequest(horizon.WebRARenewRequestParams{CertToRenewId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra renew get", func(c *Client) (horizon.Request, error) { return c.GetRenewRequest("webra.renew") }, "GET /api/v1/requests/webra.renew"},
+		{"webra renew cancel", func(c *Client) (horizon.Request, error) { return c.CancelRenewRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra revoke template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetRevokeTemplate(horizon.WebRARevokeTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra revoke submit", func(c *Client) (horizon.Request, error) {
+			return c.NewRevokeRequest(horizon.WebRARevokeRequestParams{CertificateId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra revoke get", func(c *Client) (horizon.Request, error) { return c.GetRevokeRequest("webra.revoke") }, "GET /api/v1/requests/webra.revoke"},
+		{"webra revoke cancel", func(c *Client) (horizon.Request, error) { return c.CancelRevokeRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra update template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetUpdateTemplate(horizon.WebRAUpdateTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra update submit", func(c *Client) (horizon.Request, error) {
+			return c.NewUpdateRequest(horizon.WebRAUpdateRequestParams{CertificateId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra update get", func(c *Client) (horizon.Request, error) { return c.GetUpdateRequest("webra.update") }, "GET /api/v1/requests/webra.update"},
+		{"webra update cancel", func(c *Client) (horizon.Request, error) { return c.CancelUpdateRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra migrate template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetMigrateTemplate(horizon.WebRAMigrateTemplateParams{CertificateId: "id", Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra migrate submit", func(c *Client) (horizon.Request, error) {
+			return c.NewMigrateRequest(horizon.WebRAMigrateRequestParams{CertificateId: "id", Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra migrate get", func(c *Client) (horizon.Request, error) { return c.GetMigrateRequest("webra.migrate") }, "GET /api/v1/requests/webra.migrate"},
+		{"webra migrate cancel", func(c *Client) (horizon.Request, error) { return c.CancelMigrateRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra recover template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetRecoverTemplate(horizon.WebRARecoverTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra recover submit", func(c *Client) (horizon.Request, error) {
+			return c.NewRecoverRequest(horizon.WebRARecoverRequestParams{CertificateId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra recover get", func(c *Client) (horizon.Request, error) { return c.GetRecoverRequest("webra.recover") }, "GET /api/v1/requests/webra.recover"},
+		{"webra recover cancel", func(c *Client) (horizon.Request, error) { return c.CancelRecoverRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"webra import template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetImportTemplate(horizon.WebRAImportTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"webra import submit", func(c *Client) (horizon.Request, error) {
+			return c.NewImportRequest(horizon.WebRAImportRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"webra import get", func(c *Client) (horizon.Request, error) { return c.GetImportRequest("webra.import") }, "GET /api/v1/requests/webra.import"},
+		{"webra import cancel", func(c *Client) (horizon.Request, error) { return c.CancelImportRequest("id") }, "POST /api/v1/requests/cancel"},
+		// SCEP
+		{"scep enroll template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetScepChallengeTemplate(horizon.ScepChallengeTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"scep enroll submit", func(c *Client) (horizon.Request, error) {
+			return c.NewScepChallengeRequest(horizon.ScepChallengeRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"scep enroll get", func(c *Client) (horizon.Request, error) { return c.GetScepChallengeRequest("scep.enroll") }, "GET /api/v1/requests/scep.enroll"},
+		{"scep enroll cancel", func(c *Client) (horizon.Request, error) { return c.CancelScepChallengeRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"scep renew template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetScepChallengeRenewTemplate(horizon.ScepChallengeRenewTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"scep renew submit", func(c *Client) (horizon.Request, error) {
+			return c.NewScepChallengeRenewRequest(horizon.ScepChallengeRenewRequestParams{CertificateId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"scep renew get", func(c *Client) (horizon.Request, error) { return c.GetScepChallengeRenewRequest("scep.renew") }, "GET /api/v1/requests/scep.renew"},
+		{"scep renew cancel", func(c *Client) (horizon.Request, error) { return c.CancelScepChallengeRenewRequest("id") }, "POST /api/v1/requests/cancel"},
+		// EST
+		{"est enroll template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetEstChallengeTemplate(horizon.EstChallengeTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"est enroll submit", func(c *Client) (horizon.Request, error) {
+			return c.NewEstChallengeRequest(horizon.EstChallengeRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"est enroll get", func(c *Client) (horizon.Request, error) { return c.GetEstChallengeRequest("est.enroll") }, "GET /api/v1/requests/est.enroll"},
+		{"est enroll cancel", func(c *Client) (horizon.Request, error) { return c.CancelEstChallengeRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"est renew template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetEstChallengeRenewTemplate(horizon.EstChallengeRenewTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"est renew submit", func(c *Client) (horizon.Request, error) {
+			return c.NewEstChallengeRenewRequest(horizon.EstChallengeRenewRequestParams{CertificateId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"est renew get", func(c *Client) (horizon.Request, error) { return c.GetEstChallengeRenewRequest("est.renew") }, "GET /api/v1/requests/est.renew"},
+		{"est renew cancel", func(c *Client) (horizon.Request, error) { return c.CancelEstChallengeRenewRequest("id") }, "POST /api/v1/requests/cancel"},
+		// ACME
+		{"acme enroll template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetAcmeEnrollTemplate(horizon.AcmeEnrollTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"acme enroll submit", func(c *Client) (horizon.Request, error) {
+			return c.NewAcmeEnrollRequest(horizon.AcmeEnrollRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"acme enroll get", func(c *Client) (horizon.Request, error) { return c.GetAcmeEnrollRequest("acme.enroll") }, "GET /api/v1/requests/acme.enroll"},
+		{"acme enroll cancel", func(c *Client) (horizon.Request, error) { return c.CancelAcmeEnrollRequest("id") }, "POST /api/v1/requests/cancel"},
+		// External ACME
+		{"acme-external enroll template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetAcmeExternalEnrollTemplate(horizon.AcmeExternalEnrollTemplateParams{Profile: "p"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"acme-external enroll submit", func(c *Client) (horizon.Request, error) {
+			return c.NewAcmeExternalEnrollRequest(horizon.AcmeExternalEnrollRequestParams{Profile: "p"})
+		}, "POST /api/v1/requests/submit"},
+		{"acme-external enroll get", func(c *Client) (horizon.Request, error) {
+			return c.GetAcmeExternalEnrollRequest("acme-external.enroll")
+		}, "GET /api/v1/requests/acme-external.enroll"},
+		{"acme-external enroll cancel", func(c *Client) (horizon.Request, error) { return c.CancelAcmeExternalEnrollRequest("id") }, "POST /api/v1/requests/cancel"},
+		{"acme-external renew template", func(c *Client) (horizon.Request, error) {
+			_, err := c.GetAcmeExternalRenewTemplate(horizon.AcmeExternalRenewTemplateParams{CertificateId: "id"})
+			return nil, err
+		}, "POST /api/v1/requests/template"},
+		{"acme-external renew submit", func(c *Client) (horizon.Request, error) {
+			return c.NewAcmeExternalRenewRequest(horizon.AcmeExternalRenewRequestParams{CertToRenewId: "id"})
+		}, "POST /api/v1/requests/submit"},
+		{"acme-external renew get", func(c *Client) (horizon.Request, error) {
+			return c.GetAcmeExternalRenewRequest("acme-external.renew")
+		}, "GET /api/v1/requests/acme-external.renew"},
+		{"acme-external renew cancel", func(c *Client) (horizon.Request, error) { return c.CancelAcmeExternalRenewRequest("id") }, "POST /api/v1/requests/cancel"},
+	}
+}
+
+func TestWorkflowMatrix(t *testing.T) {
+	for _, tc := range workflowMatrix() {
+		t.Run(tc.name, func(t *testing.T) {
+			c, calls := newMockClient(t)
+			if _, err := tc.call(c); err != nil {
+				t.Fatal(err.Error())
+			}
+			if len(*calls) != 1 || (*calls)[0] != tc.endpoint {
+				t.Errorf("expected a single call to %s, got %v", tc.endpoint, *calls)
+			}
+		})
+	}
+}
+
+func TestWorkflowMismatch(t *testing.T) {
+	c, _ := newMockClient(t)
+	cases := map[string]func() error{
+		"enroll fetched as renew": func() error { _, err := c.GetRenewRequest("webra.enroll"); return err },
+		"scep fetched as est":     func() error { _, err := c.GetEstChallengeRequest("scep.enroll"); return err },
+		"scep renew as enroll":    func() error { _, err := c.GetScepChallengeRequest("scep.renew"); return err },
+		"acme fetched as webra":   func() error { _, err := c.GetEnrollRequest("acme.enroll"); return err },
+		"acme-external as acme":   func() error { _, err := c.GetAcmeEnrollRequest("acme-external.enroll"); return err },
+		"recover fetched as update": func() error {
+			_, err := c.GetUpdateRequest("webra.recover")
+			return err
+		},
+	}
+	for name, call := range cases {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); err == nil {
+				t.Error("expected a type mismatch error")
+			}
+		})
+	}
+}
+
+func TestGetAnyRequest(t *testing.T) {
+	c, _ := newMockClient(t)
+	cases := map[string]horizon.Request{
+		"webra.enroll":         &horizon.WebRAEnrollRequest{},
+		"webra.renew":          &horizon.WebRARenewRequest{},
+		"webra.recover":        &horizon.WebRARecoverRequest{},
+		"scep.revoke":          &horizon.WebRARevokeRequest{},
+		"est.update":           &horizon.WebRAUpdateRequest{},
+		"webra.migrate":        &horizon.WebRAMigrateRequest{},
+		"webra.import":         &horizon.WebRAImportRequest{},
+		"scep.enroll":          &horizon.ScepChallengeRequest{},
+		"scep.renew":           &horizon.ScepChallengeRenewRequest{},
+		"est.enroll":           &horizon.EstChallengeRequest{},
+		"est.renew":            &horizon.EstChallengeRenewRequest{},
+		"acme.enroll":          &horizon.AcmeEnrollRequest{},
+		"acme-external.enroll": &horizon.AcmeExternalEnrollRequest{},
+		"acme-external.renew":  &horizon.AcmeExternalRenewRequest{},
+	}
+	for id, expected := range cases {
+		t.Run(id, func(t *testing.T) {
+			request, err := c.GetAnyRequest(id)
+			if err != nil {
+				t.Fatal(err.Error())
+			}
+			if reflect.TypeOf(request) != reflect.TypeOf(expected) {
+				t.Errorf("expected %T, got %T", expected, request)
+			}
+			if request.GetId() != id {
+				t.Errorf("expected id %s, got %s", id, request.GetId())
+			}
+		})
+	}
+	for _, id := range []string{"acme.renew", "est.recover"} {
+		if _, err := c.GetAnyRequest(id); !errors.Is(err, InvalidTypeError) {
+			t.Errorf("expected InvalidTypeError for %s, got %v", id, err)
+		}
+	}
+}
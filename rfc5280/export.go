@@ -0,0 +1,65 @@
+package rfc5280
+
+import (
+	"bytes"
+	"crypto/x509"
+	"os"
+
+	"github.com/evertrust/horizon-go"
+)
+
+type ExportOptions struct {
+	Format horizon.CertificateFormat
+	// WithChain appends the trustchain returned by Horizon to the certificate. It is not supported by the DER format
+	WithChain bool
+	Order     TrustchainOrder
+}
+
+// Export encodes a PEM certificate in the given format, along with its trustchain if requested
+func (c *Client) Export(certificatePem []byte, options ExportOptions) ([]byte, error) {
+	leaf, err := horizon.ParseCertificatePem(string(certificatePem))
+	if err != nil {
+		return nil, err
+	}
+	certificates := []*x509.Certificate{leaf}
+	if options.WithChain {
+		if certificates, err = c.chain(leaf, certificatePem, options.Order); err != nil {
+			return nil, err
+		}
+	}
+	return horizon.EncodeCertificates(certificates, options.Format)
+}
+
+// ExportFile exports a PEM certificate to a file, written atomically with the given permissions
+func (c *Client) ExportFile(certificatePem []byte, path string, perm os.FileMode, options ExportOptions) error {
+	data, err := c.Export(certificatePem, options)
+	if err != nil {
+		return err
+	}
+	return horizon.WriteFileAtomic(path, data, perm)
+}
+
+// chain returns the leaf and its trustchain in the given order
+func (c *Client) chain(leaf *x509.Certificate, certificatePem []byte, order TrustchainOrder) ([]*x509.Certificate, error) {
+	trustchain, err := c.Trustchain(certificatePem, order)
+	if err != nil {
+		return nil, err
+	}
+	var chain []*x509.Certificate
+	hasLeaf := false
+	for _, cfCertificate := range trustchain {
+		certificate, err := horizon.ParseCertificatePem(cfCertificate.Pem)
+		if err != nil {
+			return nil, err
+		}
+		hasLeaf = hasLeaf || bytes.Equal(certificate.Raw, leaf.Raw)
+		chain = append(chain, certificate)
+	}
+	if hasLeaf {
+		return chain, nil
+	}
+	if order == RootToLeaf || order == IssuingRootToLeaf {
+		return append(chain, leaf), nil
+	}
+	return append([]*x509.Certificate{leaf}, chain...), nil
+}
@@ -0,0 +1,72 @@
+package rfc5280
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	gohttp "net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"github.com/evertrust/horizon-go/http"
+)
+
+// newTestClient returns a client backed by a fake Horizon served by the handler
+func newTestClient(t *testing.T, handler gohttp.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	endpoint, _ := url.Parse(server.URL)
+	baseClient := http.Client{}
+	baseClient.SetHttpClient(nil).SetBaseUrl(*endpoint)
+	return &Client{Http: &baseClient}
+}
+
+func TestExport(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"}, NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true}
+	caDer, _ := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, caKey.Public(), caKey)
+	ca, _ := x509.ParseCertificate(caDer)
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafTemplate := x509.Certificate{SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "leaf"}, NotBefore: time.Now(), NotAfter: time.Now().Add(time.Hour)}
+	leafDer, _ := x509.CreateCertificate(rand.Reader, &leafTemplate, ca, leafKey.Public(), caKey)
+	leafPem := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leafDer})
+
+	var orders []string
+	client := newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		orders = append(orders, r.URL.Query().Get("order"))
+		w.Header().Set("Content-Type", "application/json")
+		// The trustchain returned does not include the leaf
+		_ = json.NewEncoder(w).Encode([]CfCertificate{{Pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}))}})
+	})
+
+	data, err := client.Export(leafPem, ExportOptions{Format: horizon.DER})
+	if err != nil || string(data) != string(leafDer) || len(orders) != 0 {
+		t.Errorf("DER export should only hold the leaf, got %v", err)
+	}
+
+	data, err = client.Export(leafPem, ExportOptions{Format: horizon.PEM, WithChain: true, Order: RootToLeaf})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	first, _ := pem.Decode(data)
+	if string(first.Bytes) != string(caDer) || orders[0] != "rtl" {
+		t.Error("the chain should start with the root")
+	}
+
+	data, err = client.Export(leafPem, ExportOptions{Format: horizon.PEM, WithChain: true, Order: LeafToRoot})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	first, _ = pem.Decode(data)
+	if string(first.Bytes) != string(leafDer) {
+		t.Error("the chain should start with the leaf")
+	}
+}
@@ -0,0 +1,414 @@
+package rfc5280
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	gohttp "net/http"
+	"sync"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Revocation verification
+
+var NoRevocationSourceError = errors.New("certificate has no OCSP responder nor CRL distribution point")
+var IssuerRequiredError = errors.New("issuer certificate required")
+
+type RevocationStatus string
+
+const (
+	RevocationGood    RevocationStatus = "good"
+	RevocationRevoked RevocationStatus = "revoked"
+	// RevocationUnknown is returned when no source could give a valid answer
+	RevocationUnknown RevocationStatus = "unknown"
+)
+
+type RevocationSource string
+
+const (
+	SourceOCSP RevocationSource = "ocsp"
+	SourceCRL  RevocationSource = "crl"
+)
+
+// RevocationCheck is the answer of an OCSP responder or CRL distribution point.
+// Reason is only set if the reason code is one that Horizon knows about, ReasonCode always being set for revoked certificates.
+type RevocationCheck struct {
+	Source     RevocationSource
+	Url        string
+	Status     RevocationStatus
+	RevokedAt  time.Time
+	ReasonCode int
+	Reason     horizon.RevocationReason
+	ThisUpdate time.Time
+	NextUpdate time.Time
+	// Cached is true if the answer was served from the cache
+	Cached bool
+	// Err is set if the source could not be reached or its answer is invalid, Status being RevocationUnknown
+	Err error
+}
+
+// RevocationResult aggregates the checks of a certificate: it is revoked if any source says so, good if at least one source
+// says so and none says it is revoked, and unknown otherwise
+type RevocationResult struct {
+	Status RevocationStatus
+	Checks []RevocationCheck
+}
+
+// Disagreement is a difference between the revocation state known by Horizon and the answer of a source
+type Disagreement struct {
+	Source       RevocationSource
+	Url          string
+	Field        string
+	HorizonValue string
+	SourceValue  string
+}
+
+// revocationReasons maps the RFC 5280 reason codes to the reasons known by Horizon
+var revocationReasons = map[int]horizon.RevocationReason{
+	ocsp.Unspecified:          horizon.Unspecified,
+	ocsp.KeyCompromise:        horizon.KeyCompromise,
+	ocsp.CACompromise:         horizon.CACompromise,
+	ocsp.AffiliationChanged:   horizon.AffiliationChanged,
+	ocsp.Superseded:           horizon.Superseded,
+	ocsp.CessationOfOperation: horizon.CessationOfOperation,
+}
+
+var oidCRLReasonCode = asn1.ObjectIdentifier{2, 5, 29, 21}
+
+type cachedOcsp struct {
+	check   RevocationCheck
+	expires time.Time
+}
+
+type cachedCrl struct {
+	thisUpdate time.Time
+	nextUpdate time.Time
+	// revoked maps the hex serial numbers to their entries
+	revoked map[string]RevocationCheck
+	expires time.Time
+}
+
+// RevocationChecker verifies the revocation status of certificates against the OCSP responders (AIA) and CRL distribution
+// points (CDP) of the CA, validating the signatures of the answers with the issuer certificate.
+// Answers are cached until their next update, or for CacheTTL if they do not set one. It is safe for concurrent use.
+type RevocationChecker struct {
+	// Client is used to fetch the issuer from the Horizon trustchain when it is not given, it may be nil
+	Client *Client
+	// HttpClient queries the OCSP responders and CRL distribution points, defaults to a client with a 10 seconds timeout
+	HttpClient *gohttp.Client
+	SkipOCSP   bool
+	SkipCRL    bool
+	// CacheTTL defaults to an hour
+	CacheTTL time.Duration
+
+	mutex sync.Mutex
+	ocsp  map[string]cachedOcsp
+	crls  map[string]cachedCrl
+	now   func() time.Time
+}
+
+func NewRevocationChecker(client *Client) *RevocationChecker {
+	return &RevocationChecker{Client: client}
+}
+
+func (r *RevocationChecker) init() {
+	if r.ocsp == nil {
+		r.ocsp = make(map[string]cachedOcsp)
+		r.crls = make(map[string]cachedCrl)
+	}
+	if r.now == nil {
+		r.now = time.Now
+	}
+}
+
+// CheckPem verifies the revocation status of a PEM certificate, its issuer being fetched from the Horizon trustchain
+func (r *RevocationChecker) CheckPem(certificatePem []byte) (*RevocationResult, error) {
+	certificate, err := horizon.ParseCertificatePem(string(certificatePem))
+	if err != nil {
+		return nil, err
+	}
+	if r.Client == nil {
+		return nil, IssuerRequiredError
+	}
+	chain, err := r.Client.chain(certificate, certificatePem, LeafToRoot)
+	if err != nil {
+		return nil, err
+	}
+	for _, candidate := range chain {
+		if !bytes.Equal(candidate.Raw, certificate.Raw) && certificate.CheckSignatureFrom(candidate) == nil {
+			return r.Check(certificate, candidate)
+		}
+	}
+	return nil, fmt.Errorf("%w: not found in the trustchain of %s", IssuerRequiredError, certificate.Subject.String())
+}
+
+// Check verifies the revocation status of a certificate with every OCSP responder and CRL distribution point it lists
+func (r *RevocationChecker) Check(certificate *x509.Certificate, issuer *x509.Certificate) (*RevocationResult, error) {
+	if issuer == nil {
+		return nil, IssuerRequiredError
+	}
+	r.mutex.Lock()
+	r.init()
+	r.mutex.Unlock()
+
+	result := RevocationResult{Status: RevocationUnknown}
+	if !r.SkipOCSP {
+		for _, url := range certificate.OCSPServer {
+			result.Checks = append(result.Checks, r.checkOcsp(url, certificate, issuer))
+		}
+	}
+	if !r.SkipCRL {
+		for _, url := range certificate.CRLDistributionPoints {
+			result.Checks = append(result.Checks, r.checkCrl(url, certificate, issuer))
+		}
+	}
+	if len(result.Checks) == 0 {
+		return nil, NoRevocationSourceError
+	}
+	for _, check := range result.Checks {
+		switch {
+		case check.Status == RevocationRevoked:
+			result.Status = RevocationRevoked
+		case check.Status == RevocationGood && result.Status == RevocationUnknown:
+			result.Status = RevocationGood
+		}
+	}
+	return &result, nil
+}
+
+func (r *RevocationChecker) httpClient() *gohttp.Client {
+	if r.HttpClient == nil {
+		return &gohttp.Client{Timeout: 10 * time.Second}
+	}
+	return r.HttpClient
+}
+
+// expiry returns the date until which an answer is cached
+func (r *RevocationChecker) expiry(now time.Time, nextUpdate time.Time) time.Time {
+	if !nextUpdate.IsZero() {
+		return nextUpdate
+	}
+	if r.CacheTTL > 0 {
+		return now.Add(r.CacheTTL)
+	}
+	return now.Add(time.Hour)
+}
+
+func (r *RevocationChecker) fetch(request *gohttp.Request) ([]byte, error) {
+	response, err := r.httpClient().Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != gohttp.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d from %s", response.StatusCode, request.URL.String())
+	}
+	return io.ReadAll(io.LimitReader(response.Body, 32<<20))
+}
+
+func (r *RevocationChecker) checkOcsp(url string, certificate *x509.Certificate, issuer *x509.Certificate) RevocationCheck {
+	now := r.now()
+	key := url + "|" + horizon.Thumbprint(issuer) + "|" + hex.EncodeToString(certificate.SerialNumber.Bytes())
+	r.mutex.Lock()
+	cached, found := r.ocsp[key]
+	r.mutex.Unlock()
+	if found && now.Before(cached.expires) {
+		check := cached.check
+		check.Cached = true
+		return check
+	}
+
+	check := RevocationCheck{Source: SourceOCSP, Url: url, Status: RevocationUnknown}
+	der, err := ocsp.CreateRequest(certificate, issuer, &ocsp.RequestOptions{Hash: crypto.SHA1})
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	request, err := gohttp.NewRequest("POST", url, bytes.NewReader(der))
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	request.Header.Set("Content-Type", "application/ocsp-request")
+	body, err := r.fetch(request)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	// The parser would require an embedded issuer certificate to be signed by itself, the signer is verified below instead
+	response, err := ocsp.ParseResponseForCert(body, certificate, nil)
+	if err == nil {
+		err = checkOcspSigner(response, issuer)
+	}
+	if err != nil {
+		check.Err = fmt.Errorf("invalid OCSP response from %s: %w", url, err)
+		return check
+	}
+	if err := checkValidity(now, response.ThisUpdate, response.NextUpdate); err != nil {
+		check.Err = fmt.Errorf("invalid OCSP response from %s: %w", url, err)
+		return check
+	}
+	check.ThisUpdate, check.NextUpdate = response.ThisUpdate, response.NextUpdate
+	switch response.Status {
+	case ocsp.Good:
+		check.Status = RevocationGood
+	case ocsp.Revoked:
+		check.Status = RevocationRevoked
+		check.RevokedAt = response.RevokedAt
+		check.ReasonCode = response.RevocationReason
+		check.Reason = revocationReasons[response.RevocationReason]
+	}
+	r.mutex.Lock()
+	r.ocsp[key] = cachedOcsp{check: check, expires: r.expiry(now, response.NextUpdate)}
+	r.mutex.Unlock()
+	return check
+}
+
+func (r *RevocationChecker) checkCrl(url string, certificate *x509.Certificate, issuer *x509.Certificate) RevocationCheck {
+	now := r.now()
+	key := url + "|" + horizon.Thumbprint(issuer)
+	r.mutex.Lock()
+	crl, found := r.crls[key]
+	r.mutex.Unlock()
+	cached := found && now.Before(crl.expires)
+	if !cached {
+		var err error
+		if crl, err = r.downloadCrl(url, issuer, now); err != nil {
+			return RevocationCheck{Source: SourceCRL, Url: url, Status: RevocationUnknown, Err: err}
+		}
+		r.mutex.Lock()
+		r.crls[key] = crl
+		r.mutex.Unlock()
+	}
+	check, revoked := crl.revoked[hex.EncodeToString(certificate.SerialNumber.Bytes())]
+	if !revoked {
+		check = RevocationCheck{Source: SourceCRL, Url: url, Status: RevocationGood}
+	}
+	check.ThisUpdate, check.NextUpdate = crl.thisUpdate, crl.nextUpdate
+	check.Cached = cached
+	return check
+}
+
+func (r *RevocationChecker) downloadCrl(url string, issuer *x509.Certificate, now time.Time) (cachedCrl, error) {
+	request, err := gohttp.NewRequest("GET", url, nil)
+	if err != nil {
+		return cachedCrl{}, err
+	}
+	body, err := r.fetch(request)
+	if err != nil {
+		return cachedCrl{}, err
+	}
+	// Distribution points may serve the CRL as DER or PEM
+	if block, _ := pem.Decode(body); block != nil {
+		body = block.Bytes
+	}
+	list, err := x509.ParseRevocationList(body)
+	if err != nil {
+		return cachedCrl{}, fmt.Errorf("invalid CRL from %s: %w", url, err)
+	}
+	if err := list.CheckSignatureFrom(issuer); err != nil {
+		return cachedCrl{}, fmt.Errorf("invalid CRL from %s: %w", url, err)
+	}
+	if err := checkValidity(now, list.ThisUpdate, list.NextUpdate); err != nil {
+		return cachedCrl{}, fmt.Errorf("invalid CRL from %s: %w", url, err)
+	}
+	crl := cachedCrl{
+		thisUpdate: list.ThisUpdate,
+		nextUpdate: list.NextUpdate,
+		revoked:    make(map[string]RevocationCheck),
+		expires:    r.expiry(now, list.NextUpdate),
+	}
+	// RevokedCertificateEntries, which decodes the reason codes, requires Go 1.21
+	for _, entry := range list.RevokedCertificates {
+		check := RevocationCheck{Source: SourceCRL, Url: url, Status: RevocationRevoked, RevokedAt: entry.RevocationTime}
+		for _, extension := range entry.Extensions {
+			if extension.Id.Equal(oidCRLReasonCode) {
+				var reason asn1.Enumerated
+				if _, err := asn1.Unmarshal(extension.Value, &reason); err == nil {
+					check.ReasonCode = int(reason)
+				}
+			}
+		}
+		check.Reason = revocationReasons[check.ReasonCode]
+		crl.revoked[hex.EncodeToString(entry.SerialNumber.Bytes())] = check
+	}
+	return crl, nil
+}
+
+// checkOcspSigner verifies that a response is signed by the issuer, or by a delegated responder certificate issued by it.
+// A CA signing its own responses may embed its certificate, only delegated responders need the OCSPSigning usage.
+func checkOcspSigner(response *ocsp.Response, issuer *x509.Certificate) error {
+	if response.Certificate == nil || bytes.Equal(response.Certificate.Raw, issuer.Raw) {
+		return response.CheckSignatureFrom(issuer)
+	}
+	// The parser already verified the response against the embedded certificate
+	responder := response.Certificate
+	if err := issuer.CheckSignature(responder.SignatureAlgorithm, responder.RawTBSCertificate, responder.Signature); err != nil {
+		return fmt.Errorf("responder certificate is not issued by the issuer: %w", err)
+	}
+	if !hasExtKeyUsage(responder, x509.ExtKeyUsageOCSPSigning) {
+		return errors.New("responder certificate is not authorized to sign OCSP responses")
+	}
+	return nil
+}
+
+// checkValidity rejects answers issued in the future or past their next update
+func checkValidity(now time.Time, thisUpdate time.Time, nextUpdate time.Time) error {
+	// Tolerate some clock skew with the CA
+	const skew = 5 * time.Minute
+	if thisUpdate.After(now.Add(skew)) {
+		return fmt.Errorf("issued in the future (%s)", thisUpdate.UTC().Format(time.RFC3339))
+	}
+	if !nextUpdate.IsZero() && nextUpdate.Add(skew).Before(now) {
+		return fmt.Errorf("expired since %s", nextUpdate.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+func hasExtKeyUsage(certificate *x509.Certificate, usage x509.ExtKeyUsage) bool {
+	for _, u := range certificate.ExtKeyUsage {
+		if u == usage {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareWithHorizon lists the checks disagreeing with the revocation state of the certificate in Horizon.
+// Revocation dates are compared to the second, the precision of OCSP and CRL dates. Checks in error are ignored.
+func (r *RevocationResult) CompareWithHorizon(certificate *horizon.Certificate) []Disagreement {
+	var disagreements []Disagreement
+	for _, check := range r.Checks {
+		if check.Status == RevocationUnknown {
+			continue
+		}
+		disagree := func(field string, horizonValue string, sourceValue string) {
+			disagreements = append(disagreements, Disagreement{Source: check.Source, Url: check.Url, Field: field, HorizonValue: horizonValue, SourceValue: sourceValue})
+		}
+		revoked := check.Status == RevocationRevoked
+		if certificate.Revoked != revoked {
+			disagree("revoked", fmt.Sprint(certificate.Revoked), fmt.Sprint(revoked))
+			continue
+		}
+		if !revoked {
+			continue
+		}
+		horizonDate := certificate.GetRevocationDate().Truncate(time.Second)
+		if !horizonDate.IsZero() && !horizonDate.Equal(check.RevokedAt.Truncate(time.Second)) {
+			disagree("revocationDate", horizonDate.UTC().Format(time.RFC3339), check.RevokedAt.UTC().Format(time.RFC3339))
+		}
+		if certificate.RevocationReason != "" && check.Reason != "" && certificate.RevocationReason != check.Reason {
+			disagree("revocationReason", string(certificate.RevocationReason), string(check.Reason))
+		}
+	}
+	return disagreements
+}
@@ -0,0 +1,197 @@
+package rfc5280
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"math/big"
+	gohttp "net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/evertrust/horizon-go"
+	"golang.org/x/crypto/ocsp"
+)
+
+func TestRevocationChecker(t *testing.T) {
+	revokedAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	caTemplate := x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "ca"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign | x509.KeyUsageCRLSign}
+	caDer, _ := x509.CreateCertificate(rand.Reader, &caTemplate, &caTemplate, caKey.Public(), caKey)
+	ca, _ := x509.ParseCertificate(caDer)
+	// OCSP responses are signed by a delegated responder
+	responderKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	responderTemplate := x509.Certificate{SerialNumber: big.NewInt(10), Subject: pkix.Name{CommonName: "responder"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageOCSPSigning}}
+	responderDer, _ := x509.CreateCertificate(rand.Reader, &responderTemplate, ca, responderKey.Public(), caKey)
+	responder, _ := x509.ParseCertificate(responderDer)
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	// An intermediate CA signing its own OCSP responses
+	intermediateKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	intermediateTemplate := x509.Certificate{SerialNumber: big.NewInt(20), Subject: pkix.Name{CommonName: "intermediate"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign | x509.KeyUsageCRLSign}
+	intermediateDer, _ := x509.CreateCertificate(rand.Reader, &intermediateTemplate, ca, intermediateKey.Public(), caKey)
+	intermediate, _ := x509.ParseCertificate(intermediateDer)
+
+	hits := make(map[string]int)
+	// The OCSP responder and CRL distribution points of the CA
+	server := httptest.NewServer(gohttp.HandlerFunc(func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		hits[r.URL.Path]++
+		var signer crypto.Signer = caKey
+		switch r.URL.Path {
+		case "/ocsp":
+			body, _ := io.ReadAll(r.Body)
+			request, err := ocsp.ParseRequest(body)
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			template := ocsp.Response{Status: ocsp.Good, SerialNumber: request.SerialNumber, ThisUpdate: time.Now().Add(-time.Minute), NextUpdate: time.Now().Add(time.Hour), Certificate: responder}
+			if request.SerialNumber.Int64() == 3 {
+				template.Status, template.RevokedAt, template.RevocationReason = ocsp.Revoked, revokedAt, ocsp.KeyCompromise
+			}
+			response, _ := ocsp.CreateResponse(ca, responder, template, responderKey)
+			if request.SerialNumber.Int64() == 5 {
+				// The CA signs the response itself, embedding its own certificate
+				template.Certificate = ca
+				response, _ = ocsp.CreateResponse(ca, ca, template, caKey)
+			}
+			_, _ = w.Write(response)
+		case "/intermediate/ocsp":
+			body, _ := io.ReadAll(r.Body)
+			request, err := ocsp.ParseRequest(body)
+			if err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			template := ocsp.Response{Status: ocsp.Good, SerialNumber: request.SerialNumber, ThisUpdate: time.Now().Add(-time.Minute), NextUpdate: time.Now().Add(time.Hour), Certificate: intermediate}
+			response, _ := ocsp.CreateResponse(intermediate, intermediate, template, intermediateKey)
+			_, _ = w.Write(response)
+		case "/bad.crl":
+			signer = otherKey
+			fallthrough
+		case "/crl":
+			reason, _ := asn1.Marshal(asn1.Enumerated(ocsp.KeyCompromise))
+			crl, _ := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+				Number:              big.NewInt(1),
+				ThisUpdate:          time.Now().Add(-time.Minute),
+				NextUpdate:          time.Now().Add(time.Hour),
+				RevokedCertificates: []pkix.RevokedCertificate{{SerialNumber: big.NewInt(3), RevocationTime: revokedAt, Extensions: []pkix.Extension{{Id: oidCRLReasonCode, Value: reason}}}},
+			}, ca, signer)
+			_, _ = w.Write(pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl}))
+		}
+	}))
+	defer server.Close()
+	issue := func(serial int64, crl string) *x509.Certificate {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		template := x509.Certificate{SerialNumber: big.NewInt(serial), Subject: pkix.Name{CommonName: "leaf"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+			OCSPServer: []string{server.URL + "/ocsp"}, CRLDistributionPoints: []string{server.URL + crl}}
+		der, _ := x509.CreateCertificate(rand.Reader, &template, ca, key.Public(), caKey)
+		certificate, _ := x509.ParseCertificate(der)
+		return certificate
+	}
+	// Horizon returns the CA as trustchain
+	checker := NewRevocationChecker(newTestClient(t, func(w gohttp.ResponseWriter, r *gohttp.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode([]CfCertificate{{Pem: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDer}))}})
+	}))
+
+	good := issue(2, "/crl")
+	result, err := checker.CheckPem(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: good.Raw}))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Status != RevocationGood || len(result.Checks) != 2 {
+		t.Fatalf("certificate should be good, got %+v", result)
+	}
+	if disagreements := result.CompareWithHorizon(&horizon.Certificate{}); len(disagreements) != 0 {
+		t.Errorf("unexpected disagreements %v", disagreements)
+	}
+
+	revoked := issue(3, "/crl")
+	result, err = checker.Check(revoked, ca)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	for _, check := range result.Checks {
+		if check.Err != nil || check.Status != RevocationRevoked || check.Reason != horizon.KeyCompromise || !check.RevokedAt.Equal(revokedAt) {
+			t.Errorf("unexpected %s check %+v", check.Source, check)
+		}
+		if check.Source == SourceCRL && !check.Cached {
+			t.Error("the CRL should have been cached")
+		}
+	}
+	if hits["/crl"] != 1 || hits["/ocsp"] != 2 {
+		t.Errorf("unexpected hits %v", hits)
+	}
+	disagreements := result.CompareWithHorizon(&horizon.Certificate{Revoked: true, RevocationDate: int(revokedAt.UnixMilli()), RevocationReason: horizon.Superseded})
+	if len(disagreements) != 2 || disagreements[0].Field != "revocationReason" || disagreements[0].SourceValue != string(horizon.KeyCompromise) {
+		t.Errorf("reasons should disagree, got %v", disagreements)
+	}
+	if disagreements := result.CompareWithHorizon(&horizon.Certificate{}); len(disagreements) != 2 || disagreements[0].Field != "revoked" {
+		t.Errorf("revocation status should disagree, got %v", disagreements)
+	}
+
+	// Cached OCSP answers are reused
+	if result, _ = checker.Check(revoked, ca); !result.Checks[0].Cached || hits["/ocsp"] != 2 {
+		t.Error("the OCSP response should have been cached")
+	}
+
+	// A CRL that is not signed by the issuer is rejected
+	result, err = checker.Check(issue(4, "/bad.crl"), ca)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Status != RevocationGood || result.Checks[1].Status != RevocationUnknown || result.Checks[1].Err == nil {
+		t.Errorf("the CRL signature should have been rejected, got %+v", result.Checks[1])
+	}
+
+	// The CA certificate does not need the OCSPSigning usage to sign its own responses
+	result, err = checker.Check(issue(5, "/crl"), ca)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Checks[0].Source != SourceOCSP || result.Checks[0].Err != nil || result.Checks[0].Status != RevocationGood {
+		t.Errorf("the response signed by the CA should be accepted, got %+v", result.Checks[0])
+	}
+	leafKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	leafTemplate := x509.Certificate{SerialNumber: big.NewInt(6), Subject: pkix.Name{CommonName: "leaf"}, NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour), OCSPServer: []string{server.URL + "/intermediate/ocsp"}}
+	leafDer, _ := x509.CreateCertificate(rand.Reader, &leafTemplate, intermediate, leafKey.Public(), intermediateKey)
+	leaf, _ := x509.ParseCertificate(leafDer)
+	result, err = checker.Check(leaf, intermediate)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if result.Checks[0].Err != nil || result.Checks[0].Status != RevocationGood {
+		t.Errorf("the response signed by the intermediate CA should be accepted, got %+v", result.Checks[0])
+	}
+	// The intermediate cannot sign the responses of its issuer
+	checker.mutex.Lock()
+	checker.ocsp = make(map[string]cachedOcsp)
+	checker.mutex.Unlock()
+	revoked.OCSPServer = []string{server.URL + "/intermediate/ocsp"}
+	if result, _ = checker.Check(revoked, ca); result.Checks[0].Err == nil {
+		t.Errorf("the response signed by the intermediate CA should be rejected, got %+v", result.Checks[0])
+	}
+
+	// Expired CRLs are downloaded again
+	checker.mutex.Lock()
+	for key, crl := range checker.crls {
+		crl.expires = time.Now().Add(-time.Second)
+		checker.crls[key] = crl
+	}
+	checker.mutex.Unlock()
+	if result, _ = checker.Check(revoked, ca); result.Checks[1].Cached || hits["/crl"] != 2 {
+		t.Errorf("the expired CRL should have been downloaded again, got %+v", result.Checks[1])
+	}
+
+	if _, err := checker.Check(ca, ca); err != NoRevocationSourceError {
+		t.Errorf("expected no revocation source, got %v", err)
+	}
+}
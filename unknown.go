@@ -0,0 +1,397 @@
+package horizon
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Forward compatibility
+
+// UnknownFields holds the JSON members of a model that this SDK version does not know about.
+// They are kept when decoding and emitted again when encoding, so that data sent by newer Horizon versions survives a round-trip.
+type UnknownFields map[string]json.RawMessage
+
+var knownFieldsCache sync.Map
+
+// knownFields returns the lowercased JSON names of the fields of a struct type
+func knownFields(t reflect.Type) map[string]bool {
+	if cached, ok := knownFieldsCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = true
+	}
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// ExtractUnknownFields returns the members of the JSON object data that do not match any field of model, which must be a struct.
+// Matching is case-insensitive, as it is for encoding/json.
+func ExtractUnknownFields(data []byte, model interface{}) (UnknownFields, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	known := knownFields(reflect.TypeOf(model))
+	var unknown UnknownFields
+	for name, value := range members {
+		if known[strings.ToLower(name)] {
+			continue
+		}
+		if unknown == nil {
+			unknown = make(UnknownFields)
+		}
+		unknown[name] = value
+	}
+	return unknown, nil
+}
+
+// MarshalWithUnknownFields encodes value, then adds the unknown fields to the resulting JSON object
+func MarshalWithUnknownFields(value interface{}, unknown UnknownFields) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for name, raw := range unknown {
+		if _, ok := members[name]; !ok {
+			members[name] = raw
+		}
+	}
+	return json.Marshal(members)
+}
+
+// preservedFields is embedded in the models that keep the JSON members they do not know about
+type preservedFields struct {
+	unknownFields UnknownFields
+}
+
+// UnknownFields returns the JSON members sent by Horizon that this SDK version does not know about
+func (p *preservedFields) UnknownFields() UnknownFields {
+	return p.unknownFields
+}
+
+// unmarshalWithUnknown decodes data into v, then stores the members that do not match any field of T in unknown.
+// T must be a type without an UnmarshalJSON method, usually a local type defined from the model.
+func unmarshalWithUnknown[T any](data []byte, v *T, unknown *UnknownFields) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	var model T
+	fields, err := ExtractUnknownFields(data, model)
+	*unknown = fields
+	return err
+}
+
+// Models preserving unknown fields
+
+func (r *Certificate) UnmarshalJSON(data []byte) error {
+	type certificate Certificate
+	return unmarshalWithUnknown(data, (*certificate)(r), &r.unknownFields)
+}
+
+func (r Certificate) MarshalJSON() ([]byte, error) {
+	type certificate Certificate
+	return MarshalWithUnknownFields(certificate(r), r.unknownFields)
+}
+
+func (r *WebRAEnrollTemplate) UnmarshalJSON(data []byte) error {
+	type webRAEnrollTemplate WebRAEnrollTemplate
+	if err := unmarshalWithUnknown(data, (*webRAEnrollTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r WebRAEnrollTemplate) MarshalJSON() ([]byte, error) {
+	type webRAEnrollTemplate WebRAEnrollTemplate
+	return MarshalWithUnknownFields(webRAEnrollTemplate(r), r.unknownFields)
+}
+
+func (r *WebRAEnrollRequest) UnmarshalJSON(data []byte) error {
+	type webRAEnrollRequest WebRAEnrollRequest
+	return unmarshalWithUnknown(data, (*webRAEnrollRequest)(r), &r.unknownFields)
+}
+
+func (r WebRAEnrollRequest) MarshalJSON() ([]byte, error) {
+	type webRAEnrollRequest WebRAEnrollRequest
+	return MarshalWithUnknownFields(webRAEnrollRequest(r), r.unknownFields)
+}
+
+func (r *ScepChallengeTemplate) UnmarshalJSON(data []byte) error {
+	type scepChallengeTemplate ScepChallengeTemplate
+	if err := unmarshalWithUnknown(data, (*scepChallengeTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r ScepChallengeTemplate) MarshalJSON() ([]byte, error) {
+	type scepChallengeTemplate ScepChallengeTemplate
+	return MarshalWithUnknownFields(scepChallengeTemplate(r), r.unknownFields)
+}
+
+func (r *ScepChallengeRequest) UnmarshalJSON(data []byte) error {
+	type scepChallengeRequest ScepChallengeRequest
+	return unmarshalWithUnknown(data, (*scepChallengeRequest)(r), &r.unknownFields)
+}
+
+func (r ScepChallengeRequest) MarshalJSON() ([]byte, error) {
+	type scepChallengeRequest ScepChallengeRequest
+	return MarshalWithUnknownFields(scepChallengeRequest(r), r.unknownFields)
+}
+
+func (r *ScepChallengeRenewRequest) UnmarshalJSON(data []byte) error {
+	type scepChallengeRenewRequest ScepChallengeRenewRequest
+	return unmarshalWithUnknown(data, (*scepChallengeRenewRequest)(r), &r.unknownFields)
+}
+
+func (r ScepChallengeRenewRequest) MarshalJSON() ([]byte, error) {
+	type scepChallengeRenewRequest ScepChallengeRenewRequest
+	return MarshalWithUnknownFields(scepChallengeRenewRequest(r), r.unknownFields)
+}
+
+func (r *EstChallengeTemplate) UnmarshalJSON(data []byte) error {
+	type estChallengeTemplate EstChallengeTemplate
+	if err := unmarshalWithUnknown(data, (*estChallengeTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r EstChallengeTemplate) MarshalJSON() ([]byte, error) {
+	type estChallengeTemplate EstChallengeTemplate
+	return MarshalWithUnknownFields(estChallengeTemplate(r), r.unknownFields)
+}
+
+func (r *EstChallengeRequest) UnmarshalJSON(data []byte) error {
+	type estChallengeRequest EstChallengeRequest
+	return unmarshalWithUnknown(data, (*estChallengeRequest)(r), &r.unknownFields)
+}
+
+func (r EstChallengeRequest) MarshalJSON() ([]byte, error) {
+	type estChallengeRequest EstChallengeRequest
+	return MarshalWithUnknownFields(estChallengeRequest(r), r.unknownFields)
+}
+
+func (r *EstChallengeRenewRequest) UnmarshalJSON(data []byte) error {
+	type estChallengeRenewRequest EstChallengeRenewRequest
+	return unmarshalWithUnknown(data, (*estChallengeRenewRequest)(r), &r.unknownFields)
+}
+
+func (r EstChallengeRenewRequest) MarshalJSON() ([]byte, error) {
+	type estChallengeRenewRequest EstChallengeRenewRequest
+	return MarshalWithUnknownFields(estChallengeRenewRequest(r), r.unknownFields)
+}
+
+func (r *WebRARenewTemplate) UnmarshalJSON(data []byte) error {
+	type webRARenewTemplate WebRARenewTemplate
+	return unmarshalWithUnknown(data, (*webRARenewTemplate)(r), &r.unknownFields)
+}
+
+func (r WebRARenewTemplate) MarshalJSON() ([]byte, error) {
+	type webRARenewTemplate WebRARenewTemplate
+	return MarshalWithUnknownFields(webRARenewTemplate(r), r.unknownFields)
+}
+
+func (r *WebRARenewRequest) UnmarshalJSON(data []byte) error {
+	type webRARenewRequest WebRARenewRequest
+	return unmarshalWithUnknown(data, (*webRARenewRequest)(r), &r.unknownFields)
+}
+
+func (r WebRARenewRequest) MarshalJSON() ([]byte, error) {
+	type webRARenewRequest WebRARenewRequest
+	return MarshalWithUnknownFields(webRARenewRequest(r), r.unknownFields)
+}
+
+func (r *WebRARevokeTemplate) UnmarshalJSON(data []byte) error {
+	type webRARevokeTemplate WebRARevokeTemplate
+	return unmarshalWithUnknown(data, (*webRARevokeTemplate)(r), &r.unknownFields)
+}
+
+func (r WebRARevokeTemplate) MarshalJSON() ([]byte, error) {
+	type webRARevokeTemplate WebRARevokeTemplate
+	return MarshalWithUnknownFields(webRARevokeTemplate(r), r.unknownFields)
+}
+
+func (r *WebRARevokeRequest) UnmarshalJSON(data []byte) error {
+	type webRARevokeRequest WebRARevokeRequest
+	return unmarshalWithUnknown(data, (*webRARevokeRequest)(r), &r.unknownFields)
+}
+
+func (r WebRARevokeRequest) MarshalJSON() ([]byte, error) {
+	type webRARevokeRequest WebRARevokeRequest
+	return MarshalWithUnknownFields(webRARevokeRequest(r), r.unknownFields)
+}
+
+func (r *WebRAUpdateTemplate) UnmarshalJSON(data []byte) error {
+	type webRAUpdateTemplate WebRAUpdateTemplate
+	if err := unmarshalWithUnknown(data, (*webRAUpdateTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r WebRAUpdateTemplate) MarshalJSON() ([]byte, error) {
+	type webRAUpdateTemplate WebRAUpdateTemplate
+	return MarshalWithUnknownFields(webRAUpdateTemplate(r), r.unknownFields)
+}
+
+func (r *WebRAUpdateRequest) UnmarshalJSON(data []byte) error {
+	type webRAUpdateRequest WebRAUpdateRequest
+	return unmarshalWithUnknown(data, (*webRAUpdateRequest)(r), &r.unknownFields)
+}
+
+func (r WebRAUpdateRequest) MarshalJSON() ([]byte, error) {
+	type webRAUpdateRequest WebRAUpdateRequest
+	return MarshalWithUnknownFields(webRAUpdateRequest(r), r.unknownFields)
+}
+
+func (r *WebRAMigrateTemplate) UnmarshalJSON(data []byte) error {
+	type webRAMigrateTemplate WebRAMigrateTemplate
+	if err := unmarshalWithUnknown(data, (*webRAMigrateTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r WebRAMigrateTemplate) MarshalJSON() ([]byte, error) {
+	type webRAMigrateTemplate WebRAMigrateTemplate
+	return MarshalWithUnknownFields(webRAMigrateTemplate(r), r.unknownFields)
+}
+
+func (r *WebRAMigrateRequest) UnmarshalJSON(data []byte) error {
+	type webRAMigrateRequest WebRAMigrateRequest
+	return unmarshalWithUnknown(data, (*webRAMigrateRequest)(r), &r.unknownFields)
+}
+
+func (r WebRAMigrateRequest) MarshalJSON() ([]byte, error) {
+	type webRAMigrateRequest WebRAMigrateRequest
+	return MarshalWithUnknownFields(webRAMigrateRequest(r), r.unknownFields)
+}
+
+func (r *WebRARecoverTemplate) UnmarshalJSON(data []byte) error {
+	type webRARecoverTemplate WebRARecoverTemplate
+	return unmarshalWithUnknown(data, (*webRARecoverTemplate)(r), &r.unknownFields)
+}
+
+func (r WebRARecoverTemplate) MarshalJSON() ([]byte, error) {
+	type webRARecoverTemplate WebRARecoverTemplate
+	return MarshalWithUnknownFields(webRARecoverTemplate(r), r.unknownFields)
+}
+
+func (r *WebRARecoverRequest) UnmarshalJSON(data []byte) error {
+	type webRARecoverRequest WebRARecoverRequest
+	return unmarshalWithUnknown(data, (*webRARecoverRequest)(r), &r.unknownFields)
+}
+
+func (r WebRARecoverRequest) MarshalJSON() ([]byte, error) {
+	type webRARecoverRequest WebRARecoverRequest
+	return MarshalWithUnknownFields(webRARecoverRequest(r), r.unknownFields)
+}
+
+func (r *WebRAImportTemplate) UnmarshalJSON(data []byte) error {
+	type webRAImportTemplate WebRAImportTemplate
+	if err := unmarshalWithUnknown(data, (*webRAImportTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r WebRAImportTemplate) MarshalJSON() ([]byte, error) {
+	type webRAImportTemplate WebRAImportTemplate
+	return MarshalWithUnknownFields(webRAImportTemplate(r), r.unknownFields)
+}
+
+func (r *WebRAImportRequest) UnmarshalJSON(data []byte) error {
+	type webRAImportRequest WebRAImportRequest
+	return unmarshalWithUnknown(data, (*webRAImportRequest)(r), &r.unknownFields)
+}
+
+func (r WebRAImportRequest) MarshalJSON() ([]byte, error) {
+	type webRAImportRequest WebRAImportRequest
+	return MarshalWithUnknownFields(webRAImportRequest(r), r.unknownFields)
+}
+
+func (r *AcmeEnrollTemplate) UnmarshalJSON(data []byte) error {
+	type acmeEnrollTemplate AcmeEnrollTemplate
+	if err := unmarshalWithUnknown(data, (*acmeEnrollTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r AcmeEnrollTemplate) MarshalJSON() ([]byte, error) {
+	type acmeEnrollTemplate AcmeEnrollTemplate
+	return MarshalWithUnknownFields(acmeEnrollTemplate(r), r.unknownFields)
+}
+
+func (r *AcmeEnrollRequest) UnmarshalJSON(data []byte) error {
+	type acmeEnrollRequest AcmeEnrollRequest
+	return unmarshalWithUnknown(data, (*acmeEnrollRequest)(r), &r.unknownFields)
+}
+
+func (r AcmeEnrollRequest) MarshalJSON() ([]byte, error) {
+	type acmeEnrollRequest AcmeEnrollRequest
+	return MarshalWithUnknownFields(acmeEnrollRequest(r), r.unknownFields)
+}
+
+func (r *AcmeExternalEnrollTemplate) UnmarshalJSON(data []byte) error {
+	type acmeExternalEnrollTemplate AcmeExternalEnrollTemplate
+	if err := unmarshalWithUnknown(data, (*acmeExternalEnrollTemplate)(r), &r.unknownFields); err != nil {
+		return err
+	}
+	return r.receive(data)
+}
+
+func (r AcmeExternalEnrollTemplate) MarshalJSON() ([]byte, error) {
+	type acmeExternalEnrollTemplate AcmeExternalEnrollTemplate
+	return MarshalWithUnknownFields(acmeExternalEnrollTemplate(r), r.unknownFields)
+}
+
+func (r *AcmeExternalEnrollRequest) UnmarshalJSON(data []byte) error {
+	type acmeExternalEnrollRequest AcmeExternalEnrollRequest
+	return unmarshalWithUnknown(data, (*acmeExternalEnrollRequest)(r), &r.unknownFields)
+}
+
+func (r AcmeExternalEnrollRequest) MarshalJSON() ([]byte, error) {
+	type acmeExternalEnrollRequest AcmeExternalEnrollRequest
+	return MarshalWithUnknownFields(acmeExternalEnrollRequest(r), r.unknownFields)
+}
+
+func (r *AcmeExternalRenewTemplate) UnmarshalJSON(data []byte) error {
+	type acmeExternalRenewTemplate AcmeExternalRenewTemplate
+	return unmarshalWithUnknown(data, (*acmeExternalRenewTemplate)(r), &r.unknownFields)
+}
+
+func (r AcmeExternalRenewTemplate) MarshalJSON() ([]byte, error) {
+	type acmeExternalRenewTemplate AcmeExternalRenewTemplate
+	return MarshalWithUnknownFields(acmeExternalRenewTemplate(r), r.unknownFields)
+}
+
+func (r *AcmeExternalRenewRequest) UnmarshalJSON(data []byte) error {
+	type acmeExternalRenewRequest AcmeExternalRenewRequest
+	return unmarshalWithUnknown(data, (*acmeExternalRenewRequest)(r), &r.unknownFields)
+}
+
+func (r AcmeExternalRenewRequest) MarshalJSON() ([]byte, error) {
+	type acmeExternalRenewRequest AcmeExternalRenewRequest
+	return MarshalWithUnknownFields(acmeExternalRenewRequest(r), r.unknownFields)
+}
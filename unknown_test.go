@@ -0,0 +1,82 @@
+package horizon
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCertificateUnknownFields(t *testing.T) {
+	input := `{"_id":"1","module":"webra","certificate":"pem","futureField":{"nested":[1,2]},"futureFlag":true}`
+	var certificate Certificate
+	if err := json.Unmarshal([]byte(input), &certificate); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(certificate.UnknownFields()) != 2 || string(certificate.UnknownFields()["futureFlag"]) != "true" {
+		t.Errorf("unexpected unknown fields %v", certificate.UnknownFields())
+	}
+	output, err := json.Marshal(CertificateResponse{Certificate: certificate})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var decoded struct {
+		Certificate map[string]json.RawMessage `json:"certificate"`
+	}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(decoded.Certificate["futureField"]) != `{"nested":[1,2]}` || string(decoded.Certificate["_id"]) != `"1"` {
+		t.Errorf("unknown fields were not re-emitted: %s", output)
+	}
+}
+
+func TestUpdateRequestRoundTrip(t *testing.T) {
+	input := `{"workflow":"update","module":"webra","futureRequestField":"a","template":{"owner":{"value":"john","editable":true},"futureTemplateField":"b"}}`
+	var request WebRAUpdateRequest
+	if err := json.Unmarshal([]byte(input), &request); err != nil {
+		t.Fatal(err.Error())
+	}
+	request.Template.Owner.Value = Delete
+	output, err := json.Marshal(&request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+	template := decoded["template"].(map[string]interface{})
+	if decoded["futureRequestField"] != "a" || template["futureTemplateField"] != "b" {
+		t.Errorf("unknown fields were lost: %s", output)
+	}
+	if owner := template["owner"].(map[string]interface{}); owner["value"] != nil {
+		t.Errorf("deleted owner should be sent as null: %s", output)
+	}
+}
+
+func TestNestedRoundTrip(t *testing.T) {
+	input := `{"workflow":"enroll","module":"webra","template":{"keyType":"rsa-2048","futureTemplateField":[1]},"certificate":{"_id":"1","futureCertificateField":{"a":"b"}}}`
+	var request WebRAEnrollRequest
+	if err := json.Unmarshal([]byte(input), &request); err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(request.UnknownFields()) != 0 {
+		t.Errorf("unexpected unknown fields on the request %v", request.UnknownFields())
+	}
+	if string(request.Template.UnknownFields()["futureTemplateField"]) != "[1]" || string(request.Certificate.UnknownFields()["futureCertificateField"]) != `{"a":"b"}` {
+		t.Errorf("nested unknown fields were not kept: %v %v", request.Template.UnknownFields(), request.Certificate.UnknownFields())
+	}
+	output, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var decoded struct {
+		Template    map[string]json.RawMessage `json:"template"`
+		Certificate map[string]json.RawMessage `json:"certificate"`
+	}
+	if err := json.Unmarshal(output, &decoded); err != nil {
+		t.Fatal(err.Error())
+	}
+	if string(decoded.Template["futureTemplateField"]) != "[1]" || string(decoded.Template["keyType"]) != `"rsa-2048"` || string(decoded.Certificate["futureCertificateField"]) != `{"a":"b"}` {
+		t.Errorf("nested unknown fields were not re-emitted: %s", output)
+	}
+}
@@ -0,0 +1,403 @@
+package horizon
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Template validation
+
+type ViolationCode string
+
+const (
+	ViolationMandatory     ViolationCode = "mandatory"
+	ViolationTooFewValues  ViolationCode = "min"
+	ViolationTooManyValues ViolationCode = "max"
+	ViolationNotAuthorized ViolationCode = "not_authorized"
+	ViolationInvalidValue  ViolationCode = "invalid_value"
+	ViolationNotEditable   ViolationCode = "not_editable"
+)
+
+// Violation is a constraint of a template that the filled value does not respect.
+// Field identifies the template element, e.g. "keyType", "owner", "subject[cn.1]", "sans[DNSNAME]" or "labels[env]".
+type Violation struct {
+	Field   string        `json:"field"`
+	Code    ViolationCode `json:"code"`
+	Message string        `json:"message"`
+}
+
+// TemplateValidationError is returned by the templates Validate methods and lists every violation found
+type TemplateValidationError []Violation
+
+func (e *TemplateValidationError) Error() string {
+	msg := "template does not satisfy its constraints:\n"
+	for _, violation := range *e {
+		msg = fmt.Sprintf("%s\t- %s: %s\n", msg, violation.Field, violation.Message)
+	}
+	return msg
+}
+
+type templateValidator struct {
+	violations TemplateValidationError
+}
+
+func (v *templateValidator) add(field string, code ViolationCode, format string, args ...interface{}) {
+	v.violations = append(v.violations, Violation{Field: field, Code: code, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *templateValidator) err() error {
+	if len(v.violations) == 0 {
+		return nil
+	}
+	return &v.violations
+}
+
+// templateElements are the elements of a template that Horizon may send as not editable
+type templateElements struct {
+	Subject      []IndexedDNElement   `json:"subject"`
+	Sans         []ListSANElement     `json:"sans"`
+	Extensions   []ExtensionElement   `json:"extensions"`
+	Owner        *OwnerElement        `json:"owner"`
+	Team         *TeamElement         `json:"team"`
+	ContactEmail *ContactEmailElement `json:"contactEmail"`
+	Labels       []LabelElement       `json:"labels"`
+	Metadata     []MetadataElement    `json:"metadata"`
+}
+
+// serverTemplate keeps the elements of a template as sent by Horizon, so that Validate reports the non-editable ones that were changed.
+// It is not set on templates built from scratch, whose editability is left to Horizon.
+type serverTemplate struct {
+	received *templateElements
+}
+
+func (s *serverTemplate) receive(data []byte) error {
+	var received templateElements
+	if err := json.Unmarshal(data, &received); err != nil {
+		return err
+	}
+	s.received = &received
+	return nil
+}
+
+func stringValue(s *String) string {
+	if s == nil {
+		return ""
+	}
+	return s.String
+}
+
+func nonEmptyValues(values []string) []string {
+	var nonEmpty []string
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+	return nonEmpty
+}
+
+// editable reports the elements that Horizon sent as not editable and whose value differs from the one it sent
+func (v *templateValidator) editable(received *templateElements, current templateElements) {
+	if received == nil {
+		return
+	}
+	notEditable := func(field string, name string, changed bool) {
+		if changed {
+			v.add(field, ViolationNotEditable, "%s is not editable", name)
+		}
+	}
+	for _, element := range current.Subject {
+		for _, original := range received.Subject {
+			if original.Element == element.Element && !original.Editable {
+				notEditable("subject["+element.Element+"]", "subject element "+element.Element, original.Value != element.Value)
+			}
+		}
+	}
+	for _, element := range current.Sans {
+		for _, original := range received.Sans {
+			if original.Type == element.Type && !original.Editable {
+				notEditable("sans["+element.Type+"]", element.Type+" SANs", strings.Join(nonEmptyValues(original.Value), "\n") != strings.Join(nonEmptyValues(element.Value), "\n"))
+			}
+		}
+	}
+	for _, element := range current.Extensions {
+		for _, original := range received.Extensions {
+			if original.Type == element.Type && !original.Editable {
+				notEditable("extensions["+element.Type+"]", "extension "+element.Type, original.Value != element.Value)
+			}
+		}
+	}
+	if received.Owner != nil && current.Owner != nil && !received.Owner.Editable {
+		notEditable("owner", "owner", stringValue(received.Owner.Value) != stringValue(current.Owner.Value))
+	}
+	if received.Team != nil && current.Team != nil && !received.Team.Editable {
+		notEditable("team", "team", stringValue(received.Team.Value) != stringValue(current.Team.Value))
+	}
+	if received.ContactEmail != nil && current.ContactEmail != nil && !received.ContactEmail.Editable {
+		notEditable("contactEmail", "contact email", stringValue(received.ContactEmail.Value) != stringValue(current.ContactEmail.Value))
+	}
+	for _, element := range current.Labels {
+		for _, original := range received.Labels {
+			if original.Label == element.Label && !original.Editable {
+				notEditable("labels["+element.Label+"]", "label "+element.Label, stringValue(original.Value) != stringValue(element.Value))
+			}
+		}
+	}
+	for _, element := range current.Metadata {
+		for _, original := range received.Metadata {
+			if original.Metadata == element.Metadata && !original.Editable {
+				notEditable("metadata["+string(element.Metadata)+"]", "metadata "+string(element.Metadata), stringValue(original.Value) != stringValue(element.Value))
+			}
+		}
+	}
+}
+
+func (v *templateValidator) keyType(keyType string, csr string, capabilities *Capabilities) {
+	if capabilities == nil {
+		return
+	}
+	if keyType == "" {
+		if csr != "" {
+			return
+		}
+		if capabilities.Decentralized && !capabilities.Centralized {
+			v.add("csr", ViolationMandatory, "a CSR is required")
+		} else if capabilities.Centralized && capabilities.DefaultKeyType == "" {
+			v.add("keyType", ViolationMandatory, "a key type is required")
+		}
+		return
+	}
+	if len(capabilities.AuthorizedKeyTypes) == 0 {
+		return
+	}
+	for _, authorized := range capabilities.AuthorizedKeyTypes {
+		if strings.EqualFold(authorized, keyType) {
+			return
+		}
+	}
+	v.add("keyType", ViolationNotAuthorized, "key type '%s' is not authorized (expected one of %s)", keyType, strings.Join(capabilities.AuthorizedKeyTypes, ", "))
+}
+
+func (v *templateValidator) subject(elements []IndexedDNElement) {
+	for _, element := range elements {
+		if element.Mandatory && element.Value == "" {
+			v.add("subject["+element.Element+"]", ViolationMandatory, "subject element %s is mandatory", element.Element)
+		}
+	}
+}
+
+func (v *templateValidator) sans(elements []ListSANElement) {
+	for _, element := range elements {
+		field := "sans[" + element.Type + "]"
+		count := 0
+		for _, value := range element.Value {
+			if value != "" {
+				count++
+			}
+		}
+		if count < element.Min {
+			v.add(field, ViolationTooFewValues, "at least %d %s SAN(s) required, %d given", element.Min, element.Type, count)
+		}
+		if element.Max > 0 && count > element.Max {
+			v.add(field, ViolationTooManyValues, "at most %d %s SAN(s) allowed, %d given", element.Max, element.Type, count)
+		}
+	}
+}
+
+func (v *templateValidator) extensions(elements []ExtensionElement) {
+	for _, element := range elements {
+		if element.Mandatory && element.Value == "" {
+			v.add("extensions["+element.Type+"]", ViolationMandatory, "extension %s is mandatory", element.Type)
+		}
+	}
+}
+
+func (v *templateValidator) owner(element *OwnerElement) {
+	if element != nil && element.Mandatory && element.Value == nil {
+		v.add("owner", ViolationMandatory, "owner is mandatory")
+	}
+}
+
+func (v *templateValidator) team(element *TeamElement) {
+	if element == nil {
+		return
+	}
+	if element.Value == nil {
+		if element.Mandatory {
+			v.add("team", ViolationMandatory, "team is mandatory")
+		}
+		return
+	}
+	if len(element.Authorized) == 0 {
+		return
+	}
+	for _, authorized := range element.Authorized {
+		if authorized == element.Value.String {
+			return
+		}
+	}
+	v.add("team", ViolationNotAuthorized, "team '%s' is not authorized (expected one of %s)", element.Value.String, strings.Join(element.Authorized, ", "))
+}
+
+func (v *templateValidator) contactEmail(element *ContactEmailElement) {
+	if element != nil && element.Mandatory && element.Value == nil {
+		v.add("contactEmail", ViolationMandatory, "contact email is mandatory")
+	}
+}
+
+func (v *templateValidator) labels(elements []LabelElement) {
+	for _, element := range elements {
+		if element.Mandatory && element.Value == nil {
+			v.add("labels["+element.Label+"]", ViolationMandatory, "label %s is mandatory", element.Label)
+		}
+	}
+}
+
+func (v *templateValidator) metadata(elements []MetadataElement) {
+	for _, element := range elements {
+		if _, err := GetMetadataType(string(element.Metadata)); err != nil {
+			v.add("metadata["+string(element.Metadata)+"]", ViolationInvalidValue, "unknown metadata %s", element.Metadata)
+		}
+	}
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRAEnrollTemplate) Validate() error {
+	var v templateValidator
+	v.keyType(t.KeyType, t.Csr, t.Capabilities)
+	v.subject(t.Subject)
+	v.sans(t.Sans)
+	v.extensions(t.Extensions)
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Subject: t.Subject, Sans: t.Sans, Extensions: t.Extensions, Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *ScepChallengeTemplate) Validate() error {
+	var v templateValidator
+	v.subject(t.Subject)
+	v.sans(t.Sans)
+	v.extensions(t.Extensions)
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Subject: t.Subject, Sans: t.Sans, Extensions: t.Extensions, Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *EstChallengeTemplate) Validate() error {
+	var v templateValidator
+	v.subject(t.Subject)
+	v.sans(t.Sans)
+	v.extensions(t.Extensions)
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Subject: t.Subject, Sans: t.Sans, Extensions: t.Extensions, Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRARenewTemplate) Validate() error {
+	var v templateValidator
+	v.keyType(t.KeyType, t.Csr, t.Capabilities)
+	return v.err()
+}
+
+// Validate checks that the revocation reason is a valid one.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRARevokeTemplate) Validate() error {
+	var v templateValidator
+	if t.RevocationReason != "" {
+		if _, err := ValidateRevocationReason(string(t.RevocationReason)); err != nil {
+			v.add("revocationReason", ViolationInvalidValue, err.Error())
+		}
+	}
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRAUpdateTemplate) Validate() error {
+	var v templateValidator
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRAMigrateTemplate) Validate() error {
+	var v templateValidator
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *WebRAImportTemplate) Validate() error {
+	var v templateValidator
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *AcmeEnrollTemplate) Validate() error {
+	var v templateValidator
+	v.subject(t.Subject)
+	v.sans(t.Sans)
+	v.extensions(t.Extensions)
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Subject: t.Subject, Sans: t.Sans, Extensions: t.Extensions, Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
+
+// Validate checks the template against the constraints sent by Horizon, including the editability of the elements it sent.
+// It returns a *TemplateValidationError listing the violations, or nil if the template is valid.
+func (t *AcmeExternalEnrollTemplate) Validate() error {
+	var v templateValidator
+	v.subject(t.Subject)
+	v.sans(t.Sans)
+	v.extensions(t.Extensions)
+	v.owner(t.Owner)
+	v.team(t.Team)
+	v.contactEmail(t.ContactEmail)
+	v.labels(t.Labels)
+	v.metadata(t.Metadata)
+	v.editable(t.received, templateElements{Subject: t.Subject, Sans: t.Sans, Extensions: t.Extensions, Owner: t.Owner, Team: t.Team, ContactEmail: t.ContactEmail, Labels: t.Labels, Metadata: t.Metadata})
+	return v.err()
+}
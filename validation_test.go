@@ -0,0 +1,119 @@
+package horizon
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWebRAEnrollTemplateValidate(t *testing.T) {
+	template := WebRAEnrollTemplate{
+		KeyType: "rsa-1024",
+		Subject: []IndexedDNElement{
+			{Element: "cn.1", Type: "CN", Mandatory: true},
+			{Element: "ou.1", Type: "OU"},
+		},
+		Sans: []ListSANElement{
+			{Type: "DNSNAME", Value: []string{"a.example.org", "b.example.org"}, Min: 1, Max: 1},
+			{Type: "IPADDRESS", Min: 1},
+		},
+		Owner:  &OwnerElement{Mandatory: true},
+		Team:   &TeamElement{Value: &String{"ops"}, Authorized: []string{"dev", "sec"}},
+		Labels: []LabelElement{{Label: "env", Mandatory: true}, {Label: "app"}},
+		Capabilities: &Capabilities{
+			Centralized:        true,
+			AuthorizedKeyTypes: []string{"rsa-2048", "ec-secp256r1"},
+		},
+	}
+	err := template.Validate()
+	var violations *TemplateValidationError
+	if !errors.As(err, &violations) {
+		t.Fatalf("expected a TemplateValidationError, got %v", err)
+	}
+	expected := map[string]ViolationCode{
+		"keyType":         ViolationNotAuthorized,
+		"subject[cn.1]":   ViolationMandatory,
+		"sans[DNSNAME]":   ViolationTooManyValues,
+		"sans[IPADDRESS]": ViolationTooFewValues,
+		"owner":           ViolationMandatory,
+		"team":            ViolationNotAuthorized,
+		"labels[env]":     ViolationMandatory,
+	}
+	if len(*violations) != len(expected) {
+		t.Errorf("expected %d violations, got %d: %s", len(expected), len(*violations), err.Error())
+	}
+	for _, violation := range *violations {
+		if expected[violation.Field] != violation.Code {
+			t.Errorf("unexpected violation %s (%s)", violation.Field, violation.Code)
+		}
+	}
+
+	template.KeyType = "RSA-2048"
+	template.Subject[0].Value = "example.org"
+	template.Sans[0].Value = []string{"a.example.org"}
+	template.Sans[1].Value = []string{"10.0.0.1"}
+	template.Owner.Value = &String{"john"}
+	template.Team.Value = &String{"dev"}
+	template.Labels[0].Value = &String{"prod"}
+	if err := template.Validate(); err != nil {
+		t.Errorf("expected a valid template, got %s", err.Error())
+	}
+}
+
+func TestKeyTypeOrCsrRequired(t *testing.T) {
+	decentralized := WebRAEnrollTemplate{Capabilities: &Capabilities{Decentralized: true}}
+	err := decentralized.Validate()
+	var violations *TemplateValidationError
+	if !errors.As(err, &violations) || (*violations)[0].Field != "csr" {
+		t.Errorf("expected a missing CSR violation, got %v", err)
+	}
+	centralized := WebRAEnrollTemplate{Capabilities: &Capabilities{Centralized: true, DefaultKeyType: "rsa-2048"}}
+	if err := centralized.Validate(); err != nil {
+		t.Errorf("expected the default key type to be used, got %s", err.Error())
+	}
+}
+
+func TestValidateNotEditable(t *testing.T) {
+	var template WebRAUpdateTemplate
+	err := json.Unmarshal([]byte(`{
+		"owner": {"value": "alice", "editable": false},
+		"team": {"value": "ops", "editable": true},
+		"labels": [{"label": "env", "value": "prod"}, {"label": "app", "editable": true}],
+		"metadata": [{"metadata": "pki_connector", "value": "ca1"}]
+	}`), &template)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if err := template.Validate(); err != nil {
+		t.Errorf("the template sent by Horizon should be valid, got %s", err.Error())
+	}
+
+	template.Owner.Value = &String{"bob"}
+	template.Team.Value = &String{"dev"}
+	template.Labels[0].Value = &String{"dev"}
+	template.Labels[1].Value = &String{"billing"}
+	template.Metadata = append(template.Metadata, MetadataElement{Metadata: "unknown_id", Value: &String{"1"}})
+	var violations *TemplateValidationError
+	if !errors.As(template.Validate(), &violations) {
+		t.Fatal("expected a TemplateValidationError")
+	}
+	expected := map[string]ViolationCode{
+		"owner":                ViolationNotEditable,
+		"labels[env]":          ViolationNotEditable,
+		"metadata[unknown_id]": ViolationInvalidValue,
+	}
+	if len(*violations) != len(expected) {
+		t.Errorf("expected %d violations, got %d: %s", len(expected), len(*violations), violations.Error())
+	}
+	for _, violation := range *violations {
+		if expected[violation.Field] != violation.Code {
+			t.Errorf("unexpected violation %s (%s)", violation.Field, violation.Code)
+		}
+	}
+
+	// Templates built from scratch leave the editability to Horizon
+	scratch := WebRAUpdateTemplate{Owner: &OwnerElement{Value: &String{"bob"}}}
+	if err := scratch.Validate(); err != nil {
+		t.Errorf("expected a valid template, got %s", err.Error())
+	}
+}